@@ -0,0 +1,37 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Contributors to the OpenTimelineIO project
+
+package svg
+
+import "strconv"
+
+// drawClipIndex draws a clip's 1-based index within its track in the
+// top-left corner of the clip at x, y, after the reverse marker if the clip
+// is reversed. It's drawn only where it stays clear of the centered label of
+// the given width and number of lines, beside it or above it.
+func (e *Encoder) drawClipIndex(builder *SVGBuilder, index int, x, y, width, height, labelWidth float64, labelLines int, reversed bool, fill string) error {
+	fontSize := float64(e.theme.fontSize(e.clipFontSize) - 1)
+	text := strconv.Itoa(index)
+	textWidth := estimateTextWidth(text, fontSize)
+
+	left := x + labelPadding
+	if reversed {
+		left += reverseMarkerSize + labelPadding
+	}
+	if left+textWidth+labelPadding > x+width || fontSize+2*labelPadding > height {
+		return nil
+	}
+
+	// The label is centered, with as many lines as fit its height
+	lineHeight := float64(e.theme.fontSize(e.clipFontSize)) + labelLineGap
+	labelLines = min(labelLines, max(int((height-2*labelPadding)/lineHeight), 1))
+	labelLeft := x + (width-labelWidth)/2
+	labelTop := y + height/2 - float64(labelLines)*lineHeight/2
+	beside := left+textWidth+labelPadding <= labelLeft
+	above := y+labelPadding+fontSize <= labelTop
+	if !beside && !above {
+		return nil
+	}
+
+	return builder.WriteTextWithFill(left, y+labelPadding+fontSize/2, text, "start", "", "clip-duration clip-index", fill)
+}
@@ -0,0 +1,91 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Contributors to the OpenTimelineIO project
+
+package svg
+
+import (
+	"bytes"
+	"regexp"
+	"slices"
+	"testing"
+
+	"github.com/Avalanche-io/gotio"
+	"github.com/Avalanche-io/gotio/opentime"
+)
+
+// clipIndexes returns the clip indexes drawn in an SVG, in order.
+func clipIndexes(svg string) []string {
+	var indexes []string
+	for _, m := range regexp.MustCompile(`class="clip-duration clip-index"[^>]*>([^<]*)</text>`).FindAllStringSubmatch(svg, -1) {
+		indexes = append(indexes, m[1])
+	}
+	return indexes
+}
+
+func TestShowClipIndex(t *testing.T) {
+	timeline := gotio.NewTimeline("Review", nil, nil)
+	track := gotio.NewTrack("V1", nil, gotio.TrackKindVideo, nil, nil)
+	for _, item := range []gotio.Composable{
+		newTestClip("A", 48, 24),
+		gotio.NewGapWithDuration(opentime.NewRationalTime(24, 24)),
+		newTestClip("B", 48, 24),
+		newTestClip("C", 48, 24),
+		newTestClip("Blink", 1, 24),
+	} {
+		if err := track.AppendChild(item); err != nil {
+			t.Fatalf("Failed to append item: %v", err)
+		}
+	}
+	if err := timeline.Tracks().AppendChild(track); err != nil {
+		t.Fatalf("Failed to append track: %v", err)
+	}
+
+	if indexes := clipIndexes(encodeString(t, timeline, nil)); len(indexes) != 0 {
+		t.Errorf("Clip indexes drawn by default: %v", indexes)
+	}
+
+	// Gaps aren't counted, and the one-frame clip is too narrow to number
+	svg := encodeString(t, timeline, func(enc *Encoder) {
+		enc.SetShowClipIndex(true)
+	})
+	if indexes, want := clipIndexes(svg), []string{"1", "2", "3"}; !slices.Equal(indexes, want) {
+		t.Errorf("Clip indexes = %v, want %v", indexes, want)
+	}
+
+	svg = encodeString(t, timeline, func(enc *Encoder) {
+		enc.SetShowClipIndex(true)
+		enc.SetClipIndexCountsAllItems(true)
+	})
+	if indexes, want := clipIndexes(svg), []string{"1", "3", "4"}; !slices.Equal(indexes, want) {
+		t.Errorf("Clip indexes counting all items = %v, want %v", indexes, want)
+	}
+}
+
+func TestClipIndexClearsLabel(t *testing.T) {
+	tests := []struct {
+		name               string
+		width, height      float64
+		labelWidth         float64
+		labelLines         int
+		reversed, expected bool
+	}{
+		{"above the label", 200, 76, 180, 1, false, true},
+		{"beside the label", 200, 20, 100, 1, false, true},
+		{"beside and reversed", 200, 20, 100, 1, true, true},
+		{"overlapping", 200, 20, 180, 1, false, false},
+		{"overlapping many lines", 200, 40, 180, 3, false, false},
+		{"too short", 200, 10, 0, 1, false, false},
+	}
+
+	for _, tt := range tests {
+		var buf bytes.Buffer
+		builder := NewSVGBuilder(&buf)
+		enc := NewEncoder(nil)
+		if err := enc.drawClipIndex(builder, 7, 0, 0, tt.width, tt.height, tt.labelWidth, tt.labelLines, tt.reversed, ""); err != nil {
+			t.Fatalf("drawClipIndex failed: %v", err)
+		}
+		if drawn := len(clipIndexes(buf.String())) == 1; drawn != tt.expected {
+			t.Errorf("%s: index drawn = %v, want %v", tt.name, drawn, tt.expected)
+		}
+	}
+}
@@ -0,0 +1,69 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Contributors to the OpenTimelineIO project
+
+package svg
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// parseHexColor parses a #RGB, #RRGGBB, or #RRGGBBAA color into its red,
+// green, and blue components. Any alpha component is ignored.
+func parseHexColor(hex string) (r, g, b uint8, ok bool) {
+	s := strings.TrimPrefix(hex, "#")
+	if len(s) == 3 {
+		s = string([]byte{s[0], s[0], s[1], s[1], s[2], s[2]})
+	}
+	if len(s) != 6 && len(s) != 8 {
+		return 0, 0, 0, false
+	}
+	v, err := strconv.ParseUint(s[:6], 16, 32)
+	if err != nil {
+		return 0, 0, 0, false
+	}
+	return uint8(v >> 16), uint8(v >> 8), uint8(v), true
+}
+
+// metadataColor returns the hex color stored under key in metadata,
+// normalized to #RRGGBB so an alpha can be appended to it.
+func metadataColor(metadata map[string]any, key string) (string, bool) {
+	value, ok := metadata[key].(string)
+	if !ok || !strings.HasPrefix(value, "#") {
+		return "", false
+	}
+	r, g, b, ok := parseHexColor(value)
+	if !ok {
+		return "", false
+	}
+	return fmt.Sprintf("#%02X%02X%02X", r, g, b), true
+}
+
+// relativeLuminance returns the WCAG relative luminance of an sRGB color.
+func relativeLuminance(r, g, b uint8) float64 {
+	channel := func(c uint8) float64 {
+		v := float64(c) / 255
+		if v <= 0.03928 {
+			return v / 12.92
+		}
+		return math.Pow((v+0.055)/1.055, 2.4)
+	}
+	return 0.2126*channel(r) + 0.7152*channel(g) + 0.0722*channel(b)
+}
+
+// contrastColor returns black or white, whichever contrasts more with the
+// given background color. It returns "" if the color cannot be parsed.
+func contrastColor(hex string) string {
+	r, g, b, ok := parseHexColor(hex)
+	if !ok {
+		return ""
+	}
+	// 0.179 is the luminance at which black and white text have equal
+	// contrast ratios.
+	if relativeLuminance(r, g, b) > 0.179 {
+		return "#000000"
+	}
+	return "#FFFFFF"
+}
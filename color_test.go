@@ -0,0 +1,55 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Contributors to the OpenTimelineIO project
+
+package svg
+
+import "testing"
+
+func TestContrastColor(t *testing.T) {
+	tests := []struct {
+		color    string
+		expected string
+	}{
+		{"#FFFFFF", "#000000"},
+		{"#000000", "#FFFFFF"},
+		{"#FFF", "#000000"},
+		{"#FFFF00", "#000000"},
+		{"#0000FF", "#FFFFFF"},
+		{"#3A6EA5", "#FFFFFF"},
+		{"#E0E0E0", "#000000"},
+		{"#50C878", "#000000"},
+		{"#4A90E233", "#000000"},
+		{"blue", ""},
+		{"#GGGGGG", ""},
+	}
+
+	for _, tt := range tests {
+		result := contrastColor(tt.color)
+		if result != tt.expected {
+			t.Errorf("contrastColor(%q) = %q, want %q", tt.color, result, tt.expected)
+		}
+	}
+}
+
+func TestMetadataColor(t *testing.T) {
+	tests := []struct {
+		value    any
+		expected string
+		ok       bool
+	}{
+		{"#FF8800", "#FF8800", true},
+		{"#f80", "#FF8800", true},
+		{"#ff880080", "#FF8800", true},
+		{"fed", "", false},
+		{"orange", "", false},
+		{0xFF8800, "", false},
+		{nil, "", false},
+	}
+
+	for _, tt := range tests {
+		result, ok := metadataColor(map[string]any{"color": tt.value}, "color")
+		if result != tt.expected || ok != tt.ok {
+			t.Errorf("metadataColor(%v) = %q, %v, want %q, %v", tt.value, result, ok, tt.expected, tt.ok)
+		}
+	}
+}
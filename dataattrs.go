@@ -0,0 +1,72 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Contributors to the OpenTimelineIO project
+
+package svg
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/Avalanche-io/gotio"
+	"github.com/Avalanche-io/gotio/opentime"
+)
+
+// clipDataAttrs returns the data-* attributes written on a clip's rectangle:
+// its start and duration in seconds within its parent track, unless they
+// aren't finite, its name, and, if namespace is set, each string value of
+// its metadata as data-<namespace>-<key>, once per distinct name.
+func clipDataAttrs(clip *gotio.Clip, rng opentime.TimeRange, namespace string) []Attr {
+	var attrs []Attr
+	if start := rng.StartTime().ToSeconds(); finite(start) {
+		attrs = append(attrs, Attr{"data-start", formatSeconds(start)})
+	}
+	if duration := rng.Duration().ToSeconds(); finite(duration) {
+		attrs = append(attrs, Attr{"data-duration", formatSeconds(duration)})
+	}
+	attrs = append(attrs, Attr{"data-name", clip.Name()})
+	if namespace == "" {
+		return attrs
+	}
+
+	md := clip.Metadata()
+	keys := make([]string, 0, len(md))
+	for key := range md {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	// Keys differing only in case or punctuation share a name; the first
+	// in sorted order is kept, since an element can't repeat an attribute
+	prefix := "data-" + dataName(namespace) + "-"
+	seen := make(map[string]bool, len(keys))
+	for _, key := range keys {
+		value, ok := md[key].(string)
+		name := prefix + dataName(key)
+		if !ok || seen[name] {
+			continue
+		}
+		seen[name] = true
+		attrs = append(attrs, Attr{name, value})
+	}
+	return attrs
+}
+
+// dataName converts s to a valid data attribute name part: lowercase ASCII
+// letters, digits, and hyphens.
+func dataName(s string) string {
+	var sb strings.Builder
+	for _, r := range strings.ToLower(s) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') || r == '-' {
+			sb.WriteRune(r)
+		} else {
+			sb.WriteRune('-')
+		}
+	}
+	return sb.String()
+}
+
+// formatSeconds formats seconds with as many digits as needed, e.g. "2.5".
+func formatSeconds(seconds float64) string {
+	return strconv.FormatFloat(seconds, 'f', -1, 64)
+}
@@ -0,0 +1,82 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Contributors to the OpenTimelineIO project
+
+package svg
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/Avalanche-io/gotio"
+	"github.com/Avalanche-io/gotio/opentime"
+)
+
+func TestDataName(t *testing.T) {
+	tests := map[string]string{
+		"shot":        "shot",
+		"Shot Number": "shot-number",
+		"vfx_id":      "vfx-id",
+		"take-2":      "take-2",
+	}
+	for input, expected := range tests {
+		if result := dataName(input); result != expected {
+			t.Errorf("dataName(%q) = %q, want %q", input, result, expected)
+		}
+	}
+}
+
+func TestClipDataAttrs(t *testing.T) {
+	md := gotio.AnyDictionary{
+		"Shot Number": "010",
+		"vendor":      `A&B "FX"`,
+		"take":        3,
+	}
+	sr := opentime.NewTimeRange(opentime.NewRationalTime(0, 24), opentime.NewRationalTime(60, 24))
+	clip := gotio.NewClip(`Shot "A"`, nil, &sr, md, nil, nil, "", nil)
+
+	timeline := gotio.NewTimeline("", nil, nil)
+	track := gotio.NewTrack("V1", nil, gotio.TrackKindVideo, nil, nil)
+	for _, c := range []*gotio.Clip{newTestClip("Lead", 24, 24), clip} {
+		if err := track.AppendChild(c); err != nil {
+			t.Fatalf("Failed to append clip: %v", err)
+		}
+	}
+	if err := timeline.Tracks().AppendChild(track); err != nil {
+		t.Fatalf("Failed to append track: %v", err)
+	}
+
+	svg := encodeString(t, timeline, nil)
+	if !strings.Contains(svg, `class="clip" data-start="1" data-duration="2.5" data-name="Shot &quot;A&quot;" aria-label=`) {
+		t.Error("SVG missing clip data attributes")
+	}
+	if strings.Contains(svg, "data-studio-") {
+		t.Error("Metadata should not be written without a namespace")
+	}
+
+	svg = encodeString(t, timeline, func(enc *Encoder) {
+		enc.SetDataNamespace("studio")
+	})
+	if !strings.Contains(svg, `data-name="Shot &quot;A&quot;" data-studio-shot-number="010" data-studio-vendor="A&amp;B &quot;FX&quot;" aria-label=`) {
+		t.Error("SVG missing namespaced metadata attributes")
+	}
+	if strings.Contains(svg, "data-studio-take") {
+		t.Error("Non-string metadata should not be written")
+	}
+}
+
+func TestClipDataAttrsCollidingKeys(t *testing.T) {
+	md := gotio.AnyDictionary{"vfx_id": "1", "vfx-id": "2", "VFX ID": "3"}
+	sr := opentime.NewTimeRange(opentime.NewRationalTime(0, 24), opentime.NewRationalTime(24, 24))
+	clip := gotio.NewClip("Shot", nil, &sr, md, nil, nil, "", nil)
+
+	var values []string
+	for _, attr := range clipDataAttrs(clip, sr, "ns") {
+		if attr.Name == "data-ns-vfx-id" {
+			values = append(values, attr.Value)
+		}
+	}
+	// "VFX ID" sorts first
+	if len(values) != 1 || values[0] != "3" {
+		t.Errorf("Expected one data-ns-vfx-id from the first key in sorted order, got %v", values)
+	}
+}
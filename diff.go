@@ -0,0 +1,239 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Contributors to the OpenTimelineIO project
+
+package svg
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"slices"
+
+	"github.com/Avalanche-io/gotio"
+	"github.com/Avalanche-io/gotio/opentime"
+)
+
+// Diff colors.
+const (
+	DiffAddedColor   = "#43A047"
+	DiffRemovedColor = "#E53935"
+	DiffMovedColor   = "#FB8C00"
+)
+
+// diffStatus is how a clip changed between two timelines.
+type diffStatus int
+
+const (
+	diffUnchanged diffStatus = iota
+	diffAdded
+	diffMoved
+)
+
+// diffKey identifies a clip across revisions: the same name and the same
+// source range. Clips without a source range match on duration.
+type diffKey struct {
+	name            string
+	trimmed         bool
+	start, duration float64
+}
+
+// diffEntry is a clip placed on a top-level track.
+type diffEntry struct {
+	clip  *gotio.Clip
+	key   diffKey
+	track int // index of the track in the timeline's tracks
+	rng   opentime.TimeRange
+	used  bool
+}
+
+// removedClip is a clip of the old timeline with no match in the new one.
+type removedClip struct {
+	clip  *gotio.Clip
+	track int
+	rng   opentime.TimeRange
+}
+
+// timelineDiff is the difference between an old and a new timeline.
+type timelineDiff struct {
+	status  map[*gotio.Clip]diffStatus // clips of the new timeline
+	removed []removedClip
+}
+
+// EncodeDiff encodes timeline b as SVG, colored to show how it differs from
+// timeline a. Clips only in b are drawn green, and clips of b that moved
+// since a, to another time or track, orange. Clips only in a are drawn red
+// and hatched where they used to be. Unchanged clips keep their usual color.
+//
+// Clips match when they have the same name and the same source range; each
+// clip of a matches at most one clip of b, preferring one in the same
+// place. Only clips directly on the timeline's tracks are compared, not
+// clips inside nested stacks.
+func EncodeDiff(w io.Writer, a, b *gotio.Timeline, opts ...Option) error {
+	if a == nil || b == nil {
+		return fmt.Errorf("timeline is nil")
+	}
+	enc := NewEncoder(w, opts...)
+	enc.diff = diffTimelines(a, b)
+	return enc.Encode(b)
+}
+
+// diffTimelines matches the clips of timelines a and b.
+func diffTimelines(a, b *gotio.Timeline) *timelineDiff {
+	old := diffEntries(a)
+	byKey := make(map[diffKey][]*diffEntry)
+	for _, entry := range old {
+		byKey[entry.key] = append(byKey[entry.key], entry)
+	}
+
+	d := &timelineDiff{status: make(map[*gotio.Clip]diffStatus)}
+	for _, entry := range diffEntries(b) {
+		match := matchEntry(byKey[entry.key], entry)
+		switch {
+		case match == nil:
+			d.status[entry.clip] = diffAdded
+		case match.track != entry.track || !sameTime(match.rng.StartTime(), entry.rng.StartTime()):
+			d.status[entry.clip] = diffMoved
+		default:
+			d.status[entry.clip] = diffUnchanged
+		}
+	}
+
+	for _, entry := range old {
+		if !entry.used {
+			d.removed = append(d.removed, removedClip{entry.clip, entry.track, entry.rng})
+		}
+	}
+	return d
+}
+
+// matchEntry returns the first unused candidate, preferring one in the
+// same place as entry, and marks it used.
+func matchEntry(candidates []*diffEntry, entry *diffEntry) *diffEntry {
+	var match *diffEntry
+	for _, c := range candidates {
+		if c.used {
+			continue
+		}
+		if c.track == entry.track && sameTime(c.rng.StartTime(), entry.rng.StartTime()) {
+			match = c
+			break
+		}
+		if match == nil {
+			match = c
+		}
+	}
+	if match != nil {
+		match.used = true
+	}
+	return match
+}
+
+// diffEntries returns the clips on a timeline's top-level tracks.
+func diffEntries(t *gotio.Timeline) []*diffEntry {
+	var entries []*diffEntry
+	if t.Tracks() == nil {
+		return nil
+	}
+	for i, child := range t.Tracks().Children() {
+		track, ok := child.(*gotio.Track)
+		if !ok {
+			continue
+		}
+		var cursor opentime.RationalTime
+		for j, item := range track.Children() {
+			rng, ok := childRange(track, j, cursor)
+			if !ok {
+				continue
+			}
+			if _, ok := item.(*gotio.Transition); !ok {
+				cursor = rng.EndTimeExclusive()
+			}
+			clip, ok := item.(*gotio.Clip)
+			if !ok {
+				continue
+			}
+			key := diffKey{name: clip.Name(), duration: rng.Duration().ToSeconds()}
+			if sr := clip.SourceRange(); sr != nil {
+				key.trimmed = true
+				key.start = sr.StartTime().ToSeconds()
+			}
+			entries = append(entries, &diffEntry{clip: clip, key: key, track: i, rng: rng})
+		}
+	}
+	return entries
+}
+
+// sameTime reports whether two times are equal to within a microsecond.
+func sameTime(a, b opentime.RationalTime) bool {
+	return math.Abs(a.ToSeconds()-b.ToSeconds()) < 1e-6
+}
+
+// clipStyle returns the fill color and extra class of a clip of the new
+// timeline. ok is false for unchanged clips.
+func (d *timelineDiff) clipStyle(clip *gotio.Clip) (fill, class string, ok bool) {
+	switch d.status[clip] {
+	case diffAdded:
+		return DiffAddedColor, "diff-added", true
+	case diffMoved:
+		return DiffMovedColor, "diff-moved", true
+	default:
+		return "", "", false
+	}
+}
+
+// drawRemovedClips draws the clips removed from the old timeline, hatched
+// and struck through, in the lanes of the tracks they were on.
+func (e *Encoder) drawRemovedClips(builder *SVGBuilder, l *layout, allTracks []gotio.Composable, lanes []*gotio.Track) error {
+	if len(e.diff.removed) == 0 {
+		return nil
+	}
+
+	hatchID := builder.UniqueID("diff-hatch")
+	if err := builder.WriteHatchPattern(hatchID, DiffRemovedColor); err != nil {
+		return err
+	}
+	if err := builder.StartGroup(builder.UniqueID("diff-removed"), "diff-removed"); err != nil {
+		return err
+	}
+
+	right := float64(e.marginLeft) + l.contentWidth
+	for _, removed := range e.diff.removed {
+		if removed.track >= len(allTracks) {
+			continue
+		}
+		track, ok := allTracks[removed.track].(*gotio.Track)
+		if !ok {
+			continue
+		}
+		lane := slices.Index(lanes, track)
+		if lane < 0 {
+			continue
+		}
+
+		x := l.originX + l.timeOffset(removed.rng.StartTime())
+		if !finite(x) {
+			continue
+		}
+		x2 := x + e.itemWidth(l.originX+l.timeOffset(removed.rng.EndTimeExclusive())-x)
+		x, x2 = math.Max(x, float64(e.marginLeft)), math.Min(x2, right)
+		if x2 <= x {
+			continue
+		}
+		width := x2 - x
+		y := l.tracksTop() + float64(lane*l.trackHeight) + 2
+		height := float64(l.trackHeight) - 4
+
+		title := "Removed: " + removed.clip.Name()
+		if err := builder.WriteRectWithTitle(x, y, width, height, DiffRemovedColor+"40", DiffRemovedColor, "", "diff-removed-clip", title); err != nil {
+			return err
+		}
+		if err := builder.WriteRect(x, y, width, height, "url(#"+hatchID+")", "", "", "diff-hatch", ""); err != nil {
+			return err
+		}
+		if err := builder.WriteLine(x, y+height/2, x+width, y+height/2, DiffRemovedColor, 2, "diff-strike"); err != nil {
+			return err
+		}
+	}
+
+	return builder.EndGroup()
+}
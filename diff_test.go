@@ -0,0 +1,95 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Contributors to the OpenTimelineIO project
+
+package svg
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/Avalanche-io/gotio"
+	"github.com/Avalanche-io/gotio/opentime"
+)
+
+// buildClips returns a one-track timeline of the given clips.
+func buildClips(t *testing.T, clips ...*gotio.Clip) *gotio.Timeline {
+	t.Helper()
+
+	timeline := gotio.NewTimeline("Cut", nil, nil)
+	track := gotio.NewTrack("V1", nil, gotio.TrackKindVideo, nil, nil)
+	for _, clip := range clips {
+		if err := track.AppendChild(clip); err != nil {
+			t.Fatalf("Failed to append clip: %v", err)
+		}
+	}
+	if err := timeline.Tracks().AppendChild(track); err != nil {
+		t.Fatalf("Failed to append track: %v", err)
+	}
+	return timeline
+}
+
+func TestDiffTimelines(t *testing.T) {
+	// Shot B is trimmed differently in the new cut, so it counts as removed
+	// and added; Shot C moves up to fill the space left by Shot B
+	trimmed := func(name string, start, frames float64) *gotio.Clip {
+		sr := opentime.NewTimeRange(opentime.NewRationalTime(start, 24), opentime.NewRationalTime(frames, 24))
+		return gotio.NewClip(name, nil, &sr, nil, nil, nil, "", nil)
+	}
+	a := buildClips(t, trimmed("Shot A", 0, 24), trimmed("Shot B", 0, 48), trimmed("Shot C", 0, 24))
+	newB := trimmed("Shot B", 12, 24)
+	newC := trimmed("Shot C", 0, 24)
+	newD := trimmed("Shot D", 0, 24)
+	b := buildClips(t, trimmed("Shot A", 0, 24), newB, newC, newD)
+
+	d := diffTimelines(a, b)
+
+	want := map[string]diffStatus{"Shot A": diffUnchanged, "Shot B": diffAdded, "Shot C": diffMoved, "Shot D": diffAdded}
+	for clip, status := range d.status {
+		if want[clip.Name()] != status {
+			t.Errorf("%s: got status %d, want %d", clip.Name(), status, want[clip.Name()])
+		}
+	}
+	if len(d.removed) != 1 || d.removed[0].clip.Name() != "Shot B" || d.removed[0].rng.StartTime().ToSeconds() != 1 {
+		t.Errorf("Expected the old Shot B to be removed, got %+v", d.removed)
+	}
+}
+
+func TestEncodeDiff(t *testing.T) {
+	a := buildClips(t, newTestClip("Shot A", 24, 24), newTestClip("Shot B", 24, 24), newTestClip("Shot C", 24, 24))
+	b := buildClips(t, newTestClip("Shot A", 24, 24), newTestClip("Shot C", 24, 24), newTestClip("Shot D", 24, 24))
+
+	var buf bytes.Buffer
+	if err := EncodeDiff(&buf, a, b); err != nil {
+		t.Fatalf("EncodeDiff failed: %v", err)
+	}
+	svg := buf.String()
+
+	if !strings.Contains(svg, `fill="`+DiffAddedColor+`" stroke="`+ClipBorderColor+`" id="clip-Shot_D" class="clip diff-added"`) {
+		t.Error("Added clip should be green")
+	}
+	if !strings.Contains(svg, `id="clip-Shot_C" class="clip diff-moved"`) {
+		t.Error("Moved clip should be marked")
+	}
+	if !strings.Contains(svg, `id="clip-Shot_A" class="clip"`) {
+		t.Error("Unchanged clip should keep its style")
+	}
+	if n := strings.Count(svg, `class="diff-removed-clip"`); n != 1 {
+		t.Errorf("Expected 1 removed clip, found %d", n)
+	}
+	if !strings.Contains(svg, "<title>Removed: Shot B</title>") {
+		t.Error("Removed clip should be labeled")
+	}
+	if !strings.Contains(svg, `<pattern id="diff-hatch"`) || !strings.Contains(svg, `fill="url(#diff-hatch)"`) {
+		t.Error("Removed clip should be hatched")
+	}
+
+	// Without changes nothing is marked
+	buf.Reset()
+	if err := EncodeDiff(&buf, a, a); err != nil {
+		t.Fatalf("EncodeDiff failed: %v", err)
+	}
+	if strings.Contains(buf.String(), "diff-") {
+		t.Error("Identical timelines should show no differences")
+	}
+}
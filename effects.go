@@ -0,0 +1,62 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Contributors to the OpenTimelineIO project
+
+package svg
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/Avalanche-io/gotio"
+)
+
+// badgeHeight is the height of a clip's effect badge.
+const badgeHeight = 12.0
+
+// effectBadge returns the label for a clip's effect badge and a tooltip
+// listing its effects. Time warps show their rate, e.g. "2x" or "-1x" for
+// reverse, and freeze frames show "freeze"; any other effects are counted
+// as "fx" or "fx 3". The label is empty if the clip has no effects.
+func effectBadge(clip *gotio.Clip) (label, title string) {
+	effects := clip.Effects()
+	if len(effects) == 0 {
+		return "", ""
+	}
+
+	var parts, names []string
+	other := 0
+	for _, effect := range effects {
+		name := effect.Name()
+		if name == "" {
+			name = effect.EffectName()
+		}
+
+		switch effect := effect.(type) {
+		case *gotio.FreezeFrame:
+			parts = append(parts, "freeze")
+			names = append(names, name+" (freeze frame)")
+		case *gotio.LinearTimeWarp:
+			rate := formatRate(effect.TimeScalar())
+			parts = append(parts, rate)
+			names = append(names, name+" ("+rate+")")
+		default:
+			other++
+			names = append(names, name)
+		}
+	}
+
+	switch {
+	case other == 1:
+		parts = append(parts, "fx")
+	case other > 1:
+		parts = append(parts, fmt.Sprintf("fx %d", other))
+	}
+
+	return strings.Join(parts, " "), "Effects: " + strings.Join(names, ", ")
+}
+
+// formatRate formats a time warp scalar as a playback rate, e.g. "0.5x".
+func formatRate(scalar float64) string {
+	return strconv.FormatFloat(scalar, 'f', -1, 64) + "x"
+}
@@ -0,0 +1,68 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Contributors to the OpenTimelineIO project
+
+package svg
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/Avalanche-io/gotio"
+	"github.com/Avalanche-io/gotio/opentime"
+)
+
+func TestEffectBadge(t *testing.T) {
+	tests := []struct {
+		name     string
+		effects  []gotio.Effect
+		expected string
+	}{
+		{"none", nil, ""},
+		{"speed up", []gotio.Effect{gotio.NewLinearTimeWarp("Speed", "LinearTimeWarp", 2, nil)}, "2x"},
+		{"reverse", []gotio.Effect{gotio.NewLinearTimeWarp("Reverse", "LinearTimeWarp", -1, nil)}, "-1x"},
+		{"slow", []gotio.Effect{gotio.NewLinearTimeWarp("Slow", "LinearTimeWarp", 0.5, nil)}, "0.5x"},
+		{"freeze", []gotio.Effect{&gotio.FreezeFrame{}}, "freeze"},
+		{"generic", []gotio.Effect{gotio.NewEffect("Blur", "Blur", nil)}, "fx"},
+		{"mixed", []gotio.Effect{
+			gotio.NewLinearTimeWarp("Speed", "LinearTimeWarp", 2, nil),
+			gotio.NewEffect("Blur", "Blur", nil),
+			gotio.NewEffect("Grade", "ColorCorrection", nil),
+		}, "2x fx 2"},
+	}
+
+	for _, tt := range tests {
+		clip := gotio.NewClip("Clip", nil, nil, nil, tt.effects, nil, "", nil)
+		if label, _ := effectBadge(clip); label != tt.expected {
+			t.Errorf("effectBadge(%s) = %q, want %q", tt.name, label, tt.expected)
+		}
+	}
+}
+
+func TestEffectBadgeDrawn(t *testing.T) {
+	sr := opentime.NewTimeRange(opentime.NewRationalTime(0, 24), opentime.NewRationalTime(240, 24))
+	effects := []gotio.Effect{gotio.NewLinearTimeWarp("Speed", "LinearTimeWarp", 2, nil)}
+
+	timeline := gotio.NewTimeline("Effects", nil, nil)
+	track := gotio.NewTrack("V1", nil, gotio.TrackKindVideo, nil, nil)
+	if err := track.AppendChild(gotio.NewClip("Retimed", nil, &sr, nil, effects, nil, "", nil)); err != nil {
+		t.Fatalf("Failed to append clip: %v", err)
+	}
+	if err := track.AppendChild(newTestClip("Plain", 240, 24)); err != nil {
+		t.Fatalf("Failed to append clip: %v", err)
+	}
+	if err := timeline.Tracks().AppendChild(track); err != nil {
+		t.Fatalf("Failed to append track: %v", err)
+	}
+
+	svg := encodeString(t, timeline, nil)
+
+	if n := strings.Count(svg, `class="effect-badge"`); n != 1 {
+		t.Errorf("Expected 1 effect badge, found %d", n)
+	}
+	if !strings.Contains(svg, ">2x</text>") {
+		t.Error("SVG missing effect badge label")
+	}
+	if !strings.Contains(svg, "<title>Effects: Speed (2x)</title>") {
+		t.Error("SVG missing effect badge tooltip")
+	}
+}
@@ -15,9 +15,18 @@
 package svg
 
 import (
+	"bufio"
+	"context"
 	"fmt"
 	"io"
 	"math"
+	"net/url"
+	"path"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
 
 	"github.com/Avalanche-io/gotio"
 	"github.com/Avalanche-io/gotio/opentime"
@@ -25,356 +34,2488 @@ import (
 
 // Default dimensions and styling constants.
 const (
-	DefaultWidth       = 1200
-	DefaultHeight      = 600
-	TrackHeight        = 80
-	MarginTop          = 60
-	MarginBottom       = 40
-	MarginLeft         = 100
-	MarginRight        = 40
-	RulerHeight        = 40
-	TransitionWidth    = 20
-	MinClipWidth       = 5
-	FontSize           = 12
-	SmallFontSize      = 10
+	DefaultWidth    = 1200
+	DefaultHeight   = 600
+	TrackHeight     = 80
+	MarginTop       = 60
+	MarginBottom    = 40
+	MarginLeft      = 100
+	MarginRight     = 40
+	RulerHeight     = 40
+	TransitionWidth = 20
+	MinClipWidth    = 5
+	FontSize        = 12
+	SmallFontSize   = 10
+	TitleHeight     = 40
+
+	// DefaultFontFamily is the CSS font family of all text.
+	DefaultFontFamily = "Arial, sans-serif"
+
+	// DefaultTrackColorKey is the track metadata key holding a track's
+	// display color.
+	DefaultTrackColorKey = "color"
+
+	// DefaultMuteKey and DefaultLockKey are the track metadata keys marking
+	// a track muted or locked.
+	DefaultMuteKey = "muted"
+	DefaultLockKey = "locked"
+
+	// DefaultRulerTargetMarks is the number of ruler marks aimed for.
+	DefaultRulerTargetMarks = 12
+
+	// minRulerMarkSpacing is the closest ruler marks are aimed to be, in
+	// pixels, so their labels stay readable on narrow canvases.
+	minRulerMarkSpacing = 50.0
+
+	// labelPadding is the horizontal space kept between a label and the
+	// edges of its clip.
+	labelPadding = 4
+
+	// labelLineGap is the space between the lines of a clip label.
+	labelLineGap = 2
+
+	// Font sizes in pixels of the timeline title, of small annotations such
+	// as transition names, the legend and highlight labels, of the footer,
+	// and of the empty timeline message. Like the other font sizes, they
+	// are scaled by the theme's font scale.
+	titleFontSize   = 18
+	noteFontSize    = 10
+	footerFontSize  = 9
+	messageFontSize = 14
+
+	// minWidthAccentHeight is the height of the accent drawn along the top
+	// of clips widened to the minimum width.
+	minWidthAccentHeight = 2
+
+	// ctxCheckInterval is how many items of a track are drawn between
+	// checks for cancellation.
+	ctxCheckInterval = 64
+
+	// zebraAlpha is the alpha suffix of the background of alternate tracks
+	// when zebra striping is enabled; other tracks use "33".
+	zebraAlpha = "1A"
 )
 
 // Color scheme.
 const (
-	VideoTrackColor      = "#4A90E2"
-	AudioTrackColor      = "#50C878"
-	GapColor             = "#E0E0E0"
-	TransitionColor      = "#FFB84D"
-	BackgroundColor      = "#FFFFFF"
-	GridColor            = "#CCCCCC"
-	TextColor            = "#333333"
-	RulerTextColor       = "#666666"
-	TrackLabelBg         = "#F5F5F5"
+	VideoTrackColor   = "#4A90E2"
+	AudioTrackColor   = "#50C878"
+	GapColor          = "#E0E0E0"
+	TransitionColor   = "#FFB84D"
+	BackgroundColor   = "#FFFFFF"
+	GridColor         = "#CCCCCC"
+	TextColor         = "#333333"
+	RulerTextColor    = "#666666"
+	TrackLabelBg      = "#F5F5F5"
+	ClipTextColor     = "#FFFFFF"
+	ClipBorderColor   = "#333333"
+	GapBorderColor    = "#999999"
+	GapTextColor      = "#666666"
+	PlayheadColor     = "#E53935"
+	HighlightColor    = "#FFD54F"
+	MissingMediaColor = "#D32F2F"
 )
 
+// highlight is a labeled time range drawn over all tracks.
+type highlight struct {
+	timeRange opentime.TimeRange
+	label     string
+}
+
 // Encoder encodes OTIO timelines as SVG.
+//
+// All state used while encoding, such as element ids, is created afresh by
+// each Encode call, so once configured an Encoder may encode timelines from
+// several goroutines at once, as long as each writes to its own writer with
+// EncodeTo. Setters must not be called while an Encode is running.
 type Encoder struct {
 	w      io.Writer
 	width  int
 	height int
+
+	marginTop    int
+	marginRight  int
+	marginBottom int
+	marginLeft   int
+	trackHeight  int
+
+	theme            Theme
+	autoHeight       bool
+	pixelsPerSecond  float64
+	playhead         *opentime.RationalTime
+	highlights       []highlight
+	timeFormat       TimeFormat
+	timeLabelFunc    func(opentime.RationalTime) string
+	bpm              float64
+	beatsPerBar      int
+	showLegend       bool
+	showTitle        bool
+	transitionStyle  TransitionStyle
+	showGrid         bool
+	zebraStripes     bool
+	trackSeparators  bool
+	recordLayout     bool
+	dataNamespace    string
+	idPrefix         string
+	pretty           bool
+	inline           bool
+	responsive       bool
+	useDefs          bool
+	rulerStyle       RulerStyle
+	orientation      Orientation
+	showMinimap      bool
+	dualRuler        bool
+	showTotal        bool
+	interactive      bool
+	hoverEffects     bool
+	showHandles      bool
+	expandNested     bool
+	showClipDuration bool
+	showClipIndex    bool
+	clipIndexAll     bool
+	showMediaName    bool
+	audioWaveform    bool
+	minClipWidth     float64
+	rulerMarks       int
+	fontFamily       string
+	trackFontSize    int
+	clipFontSize     int
+	rulerFontSize    int
+	font             *embeddedFont
+
+	// Hooks customizing how clips are drawn; nil means none.
+	waveformProvider  WaveformProvider
+	thumbnailProvider ThumbnailProvider
+	nestedProvider    NestedTimelineProvider
+	clipColor         func(*gotio.Clip) string
+
+	// Metadata keys read for display; "" ignores the metadata.
+	trackColorKey string
+	muteKey       string
+	lockKey       string
+	clipLabelKey  string // dotted path of a clip value shown below its name
+
+	// background overrides the theme's background color when set.
+	background            string
+	transparentBackground bool
+
+	// clock returns the time shown in the footer; nil means time.Now.
+	clock      func() time.Time
+	showFooter bool
+
+	renderEmpty bool
+
+	// zoom multiplies the time scale; 0 means no zoom.
+	zoom float64
+
+	// viewRange crops the drawing to a window of the timeline, if set.
+	viewRange *opentime.TimeRange
+
+	// title replaces the timeline name as the heading, if set.
+	title string
+
+	// diff colors clips by how they changed, for EncodeDiff.
+	diff *timelineDiff
+
+	// Corner radii of clips, gaps and track backgrounds; 0 is square.
+	clipCornerRadius  float64
+	gapCornerRadius   float64
+	trackCornerRadius float64
+
+	// mu guards lastLayout, which concurrent Encode calls may set.
+	mu sync.Mutex
+	// lastLayout holds the clip bounds recorded by the last Encode call to
+	// finish.
+	lastLayout map[string]Rect
+
+	// Which tracks are drawn as lanes
+	skipDisabledTracks bool
+	trackKinds         []string
+	groupByKind        bool
+	reverseTracks      bool
+
+	// err records the first invalid option passed to NewEncoder; it is
+	// returned from Encode.
+	err error
+}
+
+// NewEncoder creates a new SVG encoder. Options are applied in order; an
+// invalid option causes Encode to return its error.
+func NewEncoder(w io.Writer, opts ...Option) *Encoder {
+	e := &Encoder{
+		w:             w,
+		width:         DefaultWidth,
+		height:        DefaultHeight,
+		marginTop:     MarginTop,
+		marginRight:   MarginRight,
+		marginBottom:  MarginBottom,
+		marginLeft:    MarginLeft,
+		trackHeight:   TrackHeight,
+		minClipWidth:  MinClipWidth,
+		rulerMarks:    DefaultRulerTargetMarks,
+		fontFamily:    DefaultFontFamily,
+		trackColorKey: DefaultTrackColorKey,
+		muteKey:       DefaultMuteKey,
+		lockKey:       DefaultLockKey,
+		trackFontSize: FontSize,
+		clipFontSize:  SmallFontSize,
+		rulerFontSize: SmallFontSize,
+		theme:         DefaultTheme(),
+		showTitle:     true,
+		pretty:        true,
+	}
+	for _, opt := range opts {
+		if err := opt(e); err != nil && e.err == nil {
+			e.err = err
+		}
+	}
+	return e
+}
+
+// SetSize sets the SVG canvas size.
+func (e *Encoder) SetSize(width, height int) {
+	e.width = width
+	e.height = height
+}
+
+// SetWriter sets the writer that Encode writes to, so a configured encoder
+// can be reused for many timelines. Per-encode state, such as element ids,
+// always starts afresh with each Encode call.
+func (e *Encoder) SetWriter(w io.Writer) {
+	e.w = w
+}
+
+// SetTheme sets the color scheme used for rendering.
+func (e *Encoder) SetTheme(theme Theme) {
+	e.theme = theme
+}
+
+// SetBackground sets the color of the canvas background, overriding the
+// theme's background color. An empty color restores the theme's color.
+func (e *Encoder) SetBackground(color string) {
+	e.background = color
+}
+
+// SetTransparentBackground sets whether the canvas background is left
+// transparent instead of being filled with the background color.
+func (e *Encoder) SetTransparentBackground(transparent bool) {
+	e.transparentBackground = transparent
+}
+
+// SetShowFooter sets whether a footer naming the tool, its version and the
+// time of encoding is drawn right-aligned in the bottom margin.
+func (e *Encoder) SetShowFooter(show bool) {
+	e.showFooter = show
+}
+
+// SetClock sets the function that returns the time shown in the footer,
+// so output can be made reproducible. A nil clock restores time.Now.
+func (e *Encoder) SetClock(clock func() time.Time) {
+	e.clock = clock
+}
+
+// SetRenderEmpty sets whether timelines with no duration or no tracks to
+// draw are encoded as a blank canvas with the title and an "Empty timeline"
+// message, rather than returning an error.
+func (e *Encoder) SetRenderEmpty(render bool) {
+	e.renderEmpty = render
+}
+
+// SetAutoHeight enables or disables automatic canvas height. When enabled,
+// the height is computed from the number of tracks so that every track is
+// drawn at full track height, and the size set with SetSize is ignored.
+func (e *Encoder) SetAutoHeight(auto bool) {
+	e.autoHeight = auto
+}
+
+// SetPixelsPerSecond sets a fixed horizontal scale. When pps is positive,
+// the canvas width is derived from the timeline duration and the width set
+// with SetSize is ignored. A value of zero restores fit-to-width scaling.
+func (e *Encoder) SetPixelsPerSecond(pps float64) {
+	e.pixelsPerSecond = pps
+}
+
+// SetPlayhead draws a playhead marker at the given time. Times outside the
+// timeline's duration are not drawn.
+func (e *Encoder) SetPlayhead(t opentime.RationalTime) {
+	e.playhead = &t
+}
+
+// AddHighlight adds a translucent overlay spanning all tracks for the given
+// time range, with an optional label above it. Ranges extending past the
+// timeline are clamped to the content area.
+func (e *Encoder) AddHighlight(tr opentime.TimeRange, label string) {
+	e.highlights = append(e.highlights, highlight{timeRange: tr, label: label})
+}
+
+// SetTimeFormat sets how ruler labels are formatted. Timecode and frame
+// labels use the timeline's frame rate.
+func (e *Encoder) SetTimeFormat(format TimeFormat) {
+	e.timeFormat = format
+}
+
+// SetTimeLabelFunc sets a function that labels ruler ticks in place of the
+// time format, such as with bars and beats or a facility's footage codes.
+// It's given the absolute time of each tick, including the timeline's
+// global start time, at the timeline's frame rate. On a dual ruler it
+// labels the top row. A nil function restores the time format.
+func (e *Encoder) SetTimeLabelFunc(fn func(opentime.RationalTime) string) {
+	e.timeLabelFunc = fn
+}
+
+// SetMusicalGrid sets a tempo in beats per minute and a number of beats per
+// bar for the ruler and grid to follow instead of seconds. Major ticks and
+// grid lines fall on bars, labeled with the bar number counting from 1 at
+// the start of the timeline, and minor ones on beats. A dual ruler numbers
+// the bars on its bottom row, below the timecode. The tempo may be up
+// to 1000 bpm and a bar up to 64 beats long. A tempo of 0 turns the
+// musical grid off.
+func (e *Encoder) SetMusicalGrid(bpm float64, beatsPerBar int) error {
+	if bpm == 0 {
+		e.bpm, e.beatsPerBar = 0, 0
+		return nil
+	}
+	if !(bpm > 0 && bpm <= maxBPM) || beatsPerBar <= 0 || beatsPerBar > maxBeatsPerBar {
+		return fmt.Errorf("invalid musical grid (%v bpm, %d beats per bar): tempo must be in (0, %v] and beats per bar in [1, %d]", bpm, beatsPerBar, maxBPM, maxBeatsPerBar)
+	}
+	e.bpm, e.beatsPerBar = bpm, beatsPerBar
+	return nil
+}
+
+// SetShowLegend enables or disables a legend explaining the colors used for
+// clips, gaps, and transitions.
+func (e *Encoder) SetShowLegend(show bool) {
+	e.showLegend = show
+}
+
+// SetShowTitle enables or disables the timeline name heading above the
+// ruler. The title is shown by default; timelines without a name have no
+// heading.
+func (e *Encoder) SetShowTitle(show bool) {
+	e.showTitle = show
+}
+
+// SetTransitionStyle sets how transitions are drawn. The default is
+// TransitionStyleStraight.
+func (e *Encoder) SetTransitionStyle(style TransitionStyle) {
+	e.transitionStyle = style
+}
+
+// SetShowGrid enables or disables vertical grid lines through the tracks at
+// each ruler interval.
+func (e *Encoder) SetShowGrid(show bool) {
+	e.showGrid = show
+}
+
+// SetSkipDisabledTracks sets whether disabled tracks are left out of the
+// rendering entirely, rather than drawn dimmed.
+func (e *Encoder) SetSkipDisabledTracks(skip bool) {
+	e.skipDisabledTracks = skip
+}
+
+// SetTrackKindFilter limits rendering to tracks of the given kinds, such as
+// gotio.TrackKindAudio. With no kinds, all tracks are rendered.
+func (e *Encoder) SetTrackKindFilter(kinds ...string) {
+	e.trackKinds = append([]string(nil), kinds...)
+}
+
+// SetGroupByKind sets whether lanes are reordered so that all audio tracks
+// are drawn below all other tracks, separated by a divider line. Each group
+// keeps the timeline's track order.
+func (e *Encoder) SetGroupByKind(group bool) {
+	e.groupByKind = group
+}
+
+// SetReverseTrackOrder sets whether lanes are drawn in reverse track order,
+// so the last track in the timeline is at the top. When grouping by kind,
+// the order is reversed within each group.
+func (e *Encoder) SetReverseTrackOrder(reverse bool) {
+	e.reverseTracks = reverse
+}
+
+// SetZebraStripes enables or disables alternating track background shading.
+func (e *Encoder) SetZebraStripes(zebra bool) {
+	e.zebraStripes = zebra
+}
+
+// SetShowTrackSeparators enables or disables thin lines between tracks and
+// along the outer edges of the first and last tracks.
+func (e *Encoder) SetShowTrackSeparators(show bool) {
+	e.trackSeparators = show
+}
+
+// SetDataNamespace sets the namespace under which string values from clip
+// metadata are written as data-<namespace>-<key> attributes. With an empty
+// namespace, the default, metadata is not written.
+func (e *Encoder) SetDataNamespace(namespace string) {
+	e.dataNamespace = namespace
+}
+
+// SetIDPrefix sets a prefix for every element id in the SVG, so that several
+// encoded timelines can be inlined into one HTML page without their ids
+// colliding. The default is no prefix.
+func (e *Encoder) SetIDPrefix(prefix string) {
+	e.idPrefix = prefix
+}
+
+// SetPretty sets whether the SVG is indented with one element per line (the
+// default). Without pretty printing the output is written as a single
+// compact stream.
+func (e *Encoder) SetPretty(pretty bool) {
+	e.pretty = pretty
+}
+
+// SetInlineMode sets whether the XML declaration is omitted, so the SVG can
+// be embedded directly in an HTML document. The declaration is written by
+// default.
+func (e *Encoder) SetInlineMode(inline bool) {
+	e.inline = inline
+}
+
+// SetResponsive sets whether the SVG scales to fit its container. A
+// responsive SVG has no fixed width and height; its viewBox still uses the
+// configured size.
+func (e *Encoder) SetResponsive(responsive bool) {
+	e.responsive = responsive
+}
+
+// SetUseDefs sets whether track backgrounds and gaps are drawn with <use>
+// references to shapes defined once in <defs>, which makes large timelines
+// with many gaps smaller.
+func (e *Encoder) SetUseDefs(useDefs bool) {
+	e.useDefs = useDefs
+}
+
+// SetRulerStyle sets how the time axis is shown: as a full ruler above the
+// tracks (the default) or as a scale bar below them.
+func (e *Encoder) SetRulerStyle(style RulerStyle) {
+	e.rulerStyle = style
+}
+
+// SetDualRuler sets whether the ruler is drawn twice as tall with two rows
+// of labels at each tick: timecode above the time elapsed since the start
+// of the timeline in seconds. Ticks land on whole frames so both rows label
+// the same instant. It has no effect on a scale bar or a vertical layout.
+func (e *Encoder) SetDualRuler(dual bool) {
+	e.dualRuler = dual
+}
+
+// SetShowTotalDuration sets whether the duration shown is labeled at the
+// right end of the ruler, in the time format. When a view range crops the
+// timeline, the label gives the length of the view range. It has no effect
+// on a scale bar or a vertical layout.
+func (e *Encoder) SetShowTotalDuration(show bool) {
+	e.showTotal = show
+}
+
+// SetOrientation sets which way time runs. The default is
+// OrientationHorizontal.
+func (e *Encoder) SetOrientation(orientation Orientation) {
+	e.orientation = orientation
+}
+
+// SetShowMinimap sets whether a thin strip in the top margin shows the clips
+// of all tracks collapsed into one row, as an overview of the whole
+// timeline. The minimap spans the canvas width set whatever the zoom or
+// fixed scale, outlining the part in view when cropped to a view range.
+func (e *Encoder) SetShowMinimap(show bool) {
+	e.showMinimap = show
+}
+
+// SetInteractive sets whether clicking a track's label collapses or expands
+// the track, hiding its items, with a −/+ glyph beside the label showing its
+// state. This embeds a small script, which only runs where the SVG is
+// loaded as a document or inline in HTML, not as an image. Without it, all
+// tracks stay expanded and the glyphs are hidden.
+func (e *Encoder) SetInteractive(interactive bool) {
+	e.interactive = interactive
+}
+
+// SetHoverEffects sets whether clips are emphasized with a thicker border
+// and brighter fill while the pointer is over them, using CSS alone.
+func (e *Encoder) SetHoverEffects(hover bool) {
+	e.hoverEffects = hover
+}
+
+// SetShowHandles sets whether the media available beyond each end of a
+// trimmed clip is shown, as a faint strip along the bottom of the track
+// reaching out from the clip as far as the media goes.
+func (e *Encoder) SetShowHandles(show bool) {
+	e.showHandles = show
+}
+
+// SetExpandNested sets whether clips referencing nested timelines show a
+// miniature of the nested timeline's tracks along their bottom edge, rather
+// than just a badge. Only timelines the nested timeline provider supplies
+// can be drawn.
+func (e *Encoder) SetExpandNested(expand bool) {
+	e.expandNested = expand
+}
+
+// SetShowClipIndex sets whether each clip shows its 1-based position within
+// its track in its top-left corner, e.g. for referring to "clip 3 on V1" in
+// review notes. The number is only drawn where it clears the clip's label.
+func (e *Encoder) SetShowClipIndex(show bool) {
+	e.showClipIndex = show
+}
+
+// SetClipIndexCountsAllItems sets whether clip indexes count every item of
+// a track, including gaps, transitions and nested stacks, rather than only
+// its clips.
+func (e *Encoder) SetClipIndexCountsAllItems(all bool) {
+	e.clipIndexAll = all
+}
+
+// SetClipMetadataLabel sets the clip metadata value drawn below each clip's
+// name, by a dotted key path into nested dictionaries, such as "scene" or
+// "review.status". Strings, numbers and booleans are shown; clips without a
+// value at the path show nothing extra. An empty path, the default, shows
+// no metadata.
+func (e *Encoder) SetClipMetadataLabel(keyPath string) {
+	e.clipLabelKey = keyPath
+}
+
+// SetShowClipDuration sets whether each clip's duration is drawn below its
+// name, when the clip is tall enough for a second line.
+func (e *Encoder) SetShowClipDuration(show bool) {
+	e.showClipDuration = show
+}
+
+// SetShowMediaName sets whether the name of each clip's media is drawn as a
+// dimmer line below its name, when the clip is tall enough. Clips with
+// missing media show no media name.
+func (e *Encoder) SetShowMediaName(show bool) {
+	e.showMediaName = show
+}
+
+// SetAudioWaveform sets whether clips on audio tracks are drawn with a
+// placeholder waveform, so audio lanes stand out from video. OTIO holds no
+// sample data, so the waveform is made up, derived from the clip's name and
+// duration so that it's the same every time.
+func (e *Encoder) SetAudioWaveform(show bool) {
+	e.audioWaveform = show
+}
+
+// SetWaveformProvider sets where the waveforms of clips on audio tracks
+// come from. Clips it has peaks for are drawn with them; the rest fall back
+// to the placeholder waveform if enabled, or a plain rectangle. A nil
+// provider turns this off. The provider must be safe for concurrent use if
+// the encoder is.
+func (e *Encoder) SetWaveformProvider(provider WaveformProvider) {
+	e.waveformProvider = provider
+}
+
+// SetThumbnailProvider sets where the thumbnails of clips on video tracks
+// come from. Clips it has a thumbnail for show it filling the clip, behind
+// the label; the rest are drawn as before. A nil provider turns this off.
+// The provider must be safe for concurrent use if the encoder is.
+func (e *Encoder) SetThumbnailProvider(provider ThumbnailProvider) {
+	e.thumbnailProvider = provider
+}
+
+// SetNestedTimelineProvider sets where the timelines nested in clips come
+// from. Clips it has a timeline for are badged as nested, and show a
+// miniature of it if expanding nested timelines; clips whose media is an
+// OTIO file are badged either way. A nil provider turns this off. The
+// provider must be safe for concurrent use if the encoder is.
+func (e *Encoder) SetNestedTimelineProvider(provider NestedTimelineProvider) {
+	e.nestedProvider = provider
+}
+
+// SetClipColorFunc sets a function choosing the fill color of each clip,
+// such as by shot status in its metadata. Clips it returns "" for keep the
+// color of their track. A nil function colors all clips by track. The
+// function must be safe for concurrent use if the encoder is.
+func (e *Encoder) SetClipColorFunc(color func(*gotio.Clip) string) {
+	e.clipColor = color
+}
+
+// SetMinClipWidth sets the width in pixels that short items are widened to,
+// so they stay visible. Clips drawn wider than their duration are marked
+// with a thin accent. The default is MinClipWidth; negative widths are
+// treated as 0.
+func (e *Encoder) SetMinClipWidth(width float64) {
+	e.minClipWidth = max(width, 0)
+}
+
+// SetFontFamily sets the CSS font family of all text, e.g.
+// `"Helvetica Neue", Helvetica, sans-serif`. The family must not be empty
+// and must not contain characters that would break out of the style block.
+func (e *Encoder) SetFontFamily(family string) error {
+	family = strings.TrimSpace(family)
+	if family == "" {
+		return fmt.Errorf("invalid font family: must not be empty")
+	}
+	for _, r := range family {
+		if unsafeFontRune(r) {
+			return fmt.Errorf("invalid font family %q: unsupported character %q", family, r)
+		}
+	}
+	e.fontFamily = family
+	return nil
+}
+
+// unsafeFontRune reports whether r could end the CSS declaration or the
+// <style> element a font family is embedded in.
+func unsafeFontRune(r rune) bool {
+	return strings.ContainsRune("<>&{};\\", r) || unicode.IsControl(r)
+}
+
+// SetFontSizes sets the font sizes in pixels of track labels, clip labels
+// and ruler labels. The defaults are FontSize, SmallFontSize and
+// SmallFontSize. Secondary clip text, such as durations, is drawn one pixel
+// smaller than clip labels.
+func (e *Encoder) SetFontSizes(track, clip, ruler int) error {
+	if track <= 0 || clip <= 0 || ruler <= 0 {
+		return fmt.Errorf("invalid font sizes (%d, %d, %d): sizes must be positive", track, clip, ruler)
+	}
+	e.trackFontSize = track
+	e.clipFontSize = clip
+	e.rulerFontSize = ruler
+	return nil
+}
+
+// SetRulerTargetMarks sets the number of ruler marks aimed for across the
+// canvas width. The ruler picks the round interval giving about that many,
+// but never spaces marks more closely than fits their labels. The default
+// is DefaultRulerTargetMarks.
+func (e *Encoder) SetRulerTargetMarks(marks int) error {
+	if marks <= 0 {
+		return fmt.Errorf("invalid ruler target marks %d: must be positive", marks)
+	}
+	e.rulerMarks = marks
+	return nil
+}
+
+// SetClipCornerRadius sets the corner radius of clips in pixels. A radius of
+// 0, the default, draws square corners; negative radii are treated as 0.
+func (e *Encoder) SetClipCornerRadius(radius float64) {
+	e.clipCornerRadius = max(radius, 0)
+}
+
+// SetGapCornerRadius sets the corner radius of gaps in pixels, like
+// SetClipCornerRadius.
+func (e *Encoder) SetGapCornerRadius(radius float64) {
+	e.gapCornerRadius = max(radius, 0)
+}
+
+// SetTrackCornerRadius sets the corner radius of track backgrounds in
+// pixels, like SetClipCornerRadius.
+func (e *Encoder) SetTrackCornerRadius(radius float64) {
+	e.trackCornerRadius = max(radius, 0)
+}
+
+// SetZoom multiplies the horizontal scale by factor, widening the canvas
+// beyond its set width when zooming in, for display in a scrolling
+// container. The ruler adds labels to match. A factor of 1 or less than or
+// equal to 0 draws at the normal scale.
+func (e *Encoder) SetZoom(factor float64) {
+	e.zoom = factor
+}
+
+// SetViewRange crops the drawing to a window of the timeline, in time
+// relative to the start of the timeline like SetPlayhead. The window is
+// stretched across the full content width and the ruler labels the times
+// within it. Items outside the window are left out and items straddling
+// its edges are cut off.
+func (e *Encoder) SetViewRange(tr opentime.TimeRange) {
+	e.viewRange = &tr
+}
+
+// SetRecordLayout sets whether Encode records the bounds of each clip, to
+// be retrieved with LastLayout.
+func (e *Encoder) SetRecordLayout(record bool) {
+	e.recordLayout = record
+}
+
+// LastLayout returns the bounds of each clip drawn by the last Encode call,
+// keyed by the clip's element id in the SVG. It returns nil unless layout
+// recording is enabled with SetRecordLayout.
+func (e *Encoder) LastLayout() map[string]Rect {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.lastLayout
+}
+
+// setLastLayout stores the clip bounds recorded by an Encode call.
+func (e *Encoder) setLastLayout(rects map[string]Rect) {
+	e.mu.Lock()
+	e.lastLayout = rects
+	e.mu.Unlock()
+}
+
+// Encode encodes a timeline to SVG.
+func (e *Encoder) Encode(t *gotio.Timeline) error {
+	return e.EncodeContext(context.Background(), t)
+}
+
+// EncodeContext encodes a timeline to SVG like Encode, stopping early with
+// the context's error if ctx is canceled. The context is checked between
+// tracks and periodically between the items of a track; output written
+// before cancellation is incomplete.
+func (e *Encoder) EncodeContext(ctx context.Context, t *gotio.Timeline) error {
+	_, err := e.encode(ctx, e.w, t, nil)
+	return err
+}
+
+// EncodeTo encodes a timeline to SVG like Encode, writing to w instead of
+// the encoder's writer. Concurrent calls writing to different writers don't
+// interfere with each other.
+func (e *Encoder) EncodeTo(w io.Writer, t *gotio.Timeline) error {
+	_, err := e.encode(context.Background(), w, t, nil)
+	return err
+}
+
+// EncodeWithStats encodes a timeline to SVG and reports what was drawn.
+func (e *Encoder) EncodeWithStats(t *gotio.Timeline) (Stats, error) {
+	l, err := e.encode(context.Background(), e.w, t, nil)
+	if err != nil {
+		return Stats{}, err
+	}
+	stats := l.stats
+	stats.Width, stats.Height = l.width, l.height
+	return stats, nil
+}
+
+// EncodeWithLayout encodes a timeline to SVG and returns the geometry of
+// its tracks and items, in the same coordinates as the SVG.
+func (e *Encoder) EncodeWithLayout(t *gotio.Timeline) (LayoutJSON, error) {
+	doc := &LayoutJSON{}
+	l, err := e.encode(context.Background(), e.w, t, doc)
+	if err != nil {
+		return LayoutJSON{}, err
+	}
+	doc.Width, doc.Height = l.width, l.height
+	doc.Duration = l.durationSeconds
+	return *doc, nil
+}
+
+// encode encodes a timeline to SVG on w, returning the layout it was drawn
+// with. If doc is not nil, the geometry of each track and item is added to
+// it. The encoder itself is only read, apart from the recorded layout.
+func (e *Encoder) encode(ctx context.Context, w io.Writer, t *gotio.Timeline, doc *LayoutJSON) (*layout, error) {
+	e.setLastLayout(nil)
+	if e.err != nil {
+		return nil, e.err
+	}
+	if t == nil {
+		return nil, fmt.Errorf("timeline is nil")
+	}
+
+	// Get timeline duration
+	duration, err := t.Duration()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get timeline duration: %w", err)
+	}
+
+	// Get all tracks
+	tracks := t.Tracks()
+	var allTracks []gotio.Composable
+	if tracks != nil {
+		allTracks = tracks.Children()
+	}
+	lanes := e.laneTracks(allTracks)
+	numTracks := len(lanes)
+
+	// Timelines with nothing to draw are an error, or a blank canvas
+	var empty error
+	switch {
+	case duration.Value() <= 0:
+		empty = fmt.Errorf("timeline has no duration")
+	case !finite(duration.ToSeconds()):
+		empty = fmt.Errorf("timeline has an invalid duration")
+	case len(allTracks) == 0:
+		empty = fmt.Errorf("timeline has no tracks")
+	case numTracks == 0:
+		empty = fmt.Errorf("timeline has no tracks to render")
+	}
+	if e.viewRange != nil && !(e.viewRange.Duration().ToSeconds() > 0 && finite(e.viewRange.Duration().ToSeconds())) {
+		return nil, fmt.Errorf("invalid view range: duration must be positive")
+	}
+	if empty != nil {
+		if !e.renderEmpty {
+			return nil, empty
+		}
+		return e.encodeEmpty(w, t, doc)
+	}
+
+	// Make room for the title above the ruler
+	l := &layout{
+		ctx:          ctx,
+		marginTop:    e.marginTop,
+		numTracks:    numTracks,
+		rate:         timelineRate(t, duration),
+		startSeconds: globalStartSeconds(t),
+		doc:          doc,
+		stats: Stats{
+			Tracks:   numTracks,
+			Duration: duration,
+		},
+	}
+	if e.recordLayout {
+		l.clipRects = make(map[string]Rect)
+	}
+	title := ""
+	if e.showTitle {
+		title = e.titleOf(t)
+	}
+	if title != "" && l.marginTop < TitleHeight {
+		l.marginTop = TitleHeight
+	}
+	if e.showLegend && l.marginTop < legendMargin {
+		l.marginTop = legendMargin
+	}
+	showMinimap := e.showMinimap && e.orientation != OrientationVertical
+	if showMinimap && l.marginTop < minimapMargin(title, e.showLegend) {
+		l.marginTop = minimapMargin(title, e.showLegend)
+	}
+
+	// A scale bar replaces the ruler and is drawn in the bottom margin
+	l.rulerHeight = RulerHeight
+	l.marginBottom = e.marginBottom
+	if e.rulerStyle == RulerStyleScaleBar {
+		l.rulerHeight = 0
+		if l.marginBottom < scaleBarHeight {
+			l.marginBottom = scaleBarHeight
+		}
+	} else if e.dualRuler {
+		l.rulerHeight = 2 * RulerHeight
+		l.dualRuler = true
+	}
+
+	if e.orientation == OrientationVertical {
+		e.layoutVertical(l, duration)
+	} else {
+		e.layoutHorizontal(l, duration)
+	}
+
+	// Batch the many small element writes. The deferred flush writes out
+	// whatever was drawn if encoding fails part way; after the final flush
+	// below it does nothing.
+	bw := bufio.NewWriter(w)
+	defer bw.Flush()
+	builder := e.newBuilder(bw)
+
+	// Write SVG header
+	if err := builder.WriteHeader(l.width, l.height); err != nil {
+		return nil, err
+	}
+
+	// Write accessible title and description
+	if err := builder.WriteTitleDesc(documentTitle(t), documentDesc(allTracks, duration.ToSeconds())); err != nil {
+		return nil, err
+	}
+
+	// Draw the background first so everything else draws on top
+	if err := e.drawBackground(builder, l); err != nil {
+		return nil, err
+	}
+
+	// Write CSS styles
+	if err := e.writeStyles(builder); err != nil {
+		return nil, err
+	}
+
+	// Define shared shapes
+	if e.useDefs {
+		if err := e.writeDefs(builder, l); err != nil {
+			return nil, err
+		}
+	}
+
+	// Items are clipped to the view range
+	if e.viewRange != nil {
+		l.viewClipID = builder.UniqueID("view-clip")
+		x, y, width, height := l.place(l.contentLeft, l.tracksTop(), l.contentWidth, l.tracksBottom()-l.tracksTop())
+		if err := builder.WriteClipRect(l.viewClipID, x, y, width, height); err != nil {
+			return nil, err
+		}
+	}
+
+	// Draw title and legend in the top margin, side by side
+	if title != "" && e.showLegend {
+		legendX, _ := e.legendPosition(l)
+		title = truncateText(title, legendX-labelPadding-float64(e.marginLeft), float64(e.theme.fontSize(titleFontSize)))
+	}
+	if title != "" {
+		if err := builder.WriteText(float64(e.marginLeft), float64(l.marginTop)/2, title, "start", "", "timeline-title"); err != nil {
+			return nil, err
+		}
+	}
+
+	if e.showLegend {
+		if err := e.drawLegend(builder, l); err != nil {
+			return nil, err
+		}
+	}
+
+	// Draw the minimap just above the ruler
+	if showMinimap {
+		if err := e.drawMinimap(builder, l, lanes); err != nil {
+			return nil, err
+		}
+	}
+
+	// Draw time ruler at top, or a scale bar at the bottom
+	if e.rulerStyle == RulerStyleScaleBar && !l.vertical {
+		if err := e.drawScaleBar(builder, l); err != nil {
+			return nil, err
+		}
+	} else {
+		if err := e.drawTimeRuler(builder, l); err != nil {
+			return nil, err
+		}
+	}
+
+	// Draw timeline markers along the bottom of the ruler
+	if markers := tracks.Markers(); len(markers) > 0 && !l.vertical {
+		if err := e.drawTimelineMarkers(builder, l, markers); err != nil {
+			return nil, err
+		}
+	}
+
+	// Draw grid behind the tracks
+	if e.showGrid {
+		if err := e.drawGrid(builder, l); err != nil {
+			return nil, err
+		}
+	}
+
+	// Draw each track
+	for i, track := range lanes {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		yOffset := l.tracksTop() + float64(i*l.trackHeight)
+		if err := e.drawTrack(builder, l, track, i, yOffset, float64(l.trackHeight)); err != nil {
+			return nil, err
+		}
+	}
+
+	// Outline the lanes, under the kind divider
+	if e.trackSeparators {
+		if err := e.drawTrackSeparators(builder, l); err != nil {
+			return nil, err
+		}
+	}
+
+	// Separate the video and audio groups
+	if e.groupByKind {
+		if err := e.drawKindDivider(builder, l, lanes); err != nil {
+			return nil, err
+		}
+	}
+
+	// Show where clips were removed, for EncodeDiff
+	if e.diff != nil && !l.vertical {
+		if err := e.drawRemovedClips(builder, l, allTracks, lanes); err != nil {
+			return nil, err
+		}
+	}
+
+	// Draw highlights on top of the tracks
+	if len(e.highlights) > 0 {
+		if err := e.drawHighlights(builder, l); err != nil {
+			return nil, err
+		}
+	}
+
+	// Draw playhead on top of the tracks
+	if e.playhead != nil {
+		if err := e.drawPlayhead(builder, l, *e.playhead); err != nil {
+			return nil, err
+		}
+	}
+
+	// Draw the generation footer in the bottom margin
+	if e.showFooter {
+		if err := e.drawFooter(builder, l); err != nil {
+			return nil, err
+		}
+	}
+
+	// Add the script last, so everything it finds has been drawn
+	if e.interactive {
+		if err := builder.WriteScript(trackToggleScript); err != nil {
+			return nil, err
+		}
+	}
+
+	// Write SVG footer
+	if err := builder.WriteFooter(); err != nil {
+		return nil, err
+	}
+	if err := bw.Flush(); err != nil {
+		return nil, err
+	}
+
+	e.setLastLayout(l.clipRects)
+	return l, nil
+}
+
+// layoutHorizontal lays out tracks as rows below a ruler, with time running
+// left to right.
+func (e *Encoder) layoutHorizontal(l *layout, duration opentime.RationalTime) {
+	// Calculate canvas height and per-track height
+	l.height = e.height
+	l.trackHeight = e.trackHeight
+	if e.autoHeight {
+		l.height = l.marginTop + l.rulerHeight + l.numTracks*l.trackHeight + l.marginBottom
+	} else {
+		availableHeight := float64(l.height - l.marginTop - l.rulerHeight - l.marginBottom)
+		l.trackHeight = e.laneSize(availableHeight, l.numTracks)
+	}
+
+	l.width = e.width
+	l.contentLeft = float64(e.marginLeft)
+	if e.scaleTimeAxis(l, duration, float64(l.width-e.marginLeft-e.marginRight)) {
+		l.width = e.marginLeft + int(math.Ceil(l.contentWidth)) + e.marginRight
+	}
+}
+
+// laneSize returns the size of each of n tracks sharing available pixels,
+// at most the track height set but no less than 40 pixels.
+func (e *Encoder) laneSize(available float64, n int) int {
+	size := int(available / float64(n))
+	if size > e.trackHeight {
+		size = e.trackHeight
+	}
+	if size < 40 && e.trackHeight >= 40 {
+		size = 40
+	}
+	return size
+}
+
+// scaleTimeAxis sets the scale of the time axis, which starts at
+// l.contentLeft, to fit the duration shown into length pixels. It reports
+// whether a fixed scale or zoom changed the length, so the canvas must be
+// resized to fit.
+func (e *Encoder) scaleTimeAxis(l *layout, duration opentime.RationalTime, length float64) bool {
+	// Calculate scale: pixels per second, over the view range if cropped
+	viewStart := 0.0
+	l.contentWidth = length
+	l.durationSeconds = duration.ToSeconds()
+	if e.viewRange != nil {
+		viewStart = e.viewRange.StartTime().ToSeconds()
+		l.durationSeconds = e.viewRange.Duration().ToSeconds()
+		l.startSeconds += viewStart
+		l.viewStart = viewStart
+	}
+	if !(l.durationSeconds >= minDurationSeconds) {
+		l.durationSeconds = minDurationSeconds
+	}
+	l.timeScale = l.contentWidth / l.durationSeconds
+	resized := false
+	if e.pixelsPerSecond > 0 {
+		l.timeScale = e.pixelsPerSecond
+		resized = true
+	}
+	l.zoom = 1
+	if e.zoom > 0 && e.zoom != 1 {
+		l.zoom = e.zoom
+		l.timeScale *= e.zoom
+		resized = true
+	}
+	if resized {
+		l.contentWidth = l.durationSeconds * l.timeScale
+	}
+	l.originX = l.contentLeft - viewStart*l.timeScale
+	return resized
+}
+
+// encodeEmpty draws a timeline with nothing to show as a blank canvas with
+// its title and a centered message.
+func (e *Encoder) encodeEmpty(w io.Writer, t *gotio.Timeline, doc *LayoutJSON) (*layout, error) {
+	l := &layout{
+		width:        e.width,
+		height:       e.height,
+		marginTop:    e.marginTop,
+		marginBottom: e.marginBottom,
+		doc:          doc,
+	}
+	if e.recordLayout {
+		l.clipRects = make(map[string]Rect)
+	}
+	title := ""
+	if e.showTitle {
+		title = e.titleOf(t)
+	}
+	if title != "" && l.marginTop < TitleHeight {
+		l.marginTop = TitleHeight
+	}
+	if e.autoHeight {
+		l.height = l.marginTop + e.trackHeight + l.marginBottom
+	}
+
+	bw := bufio.NewWriter(w)
+	defer bw.Flush()
+	builder := e.newBuilder(bw)
+
+	if err := builder.WriteHeader(l.width, l.height); err != nil {
+		return nil, err
+	}
+	if err := builder.WriteTitleDesc(documentTitle(t), "Empty timeline"); err != nil {
+		return nil, err
+	}
+	if err := e.drawBackground(builder, l); err != nil {
+		return nil, err
+	}
+	if err := e.writeStyles(builder); err != nil {
+		return nil, err
+	}
+	if title != "" {
+		if err := builder.WriteText(float64(e.marginLeft), float64(l.marginTop)/2, title, "start", "", "timeline-title"); err != nil {
+			return nil, err
+		}
+	}
+	if err := builder.WriteText(float64(l.width)/2, float64(l.height)/2, "Empty timeline", "middle", builder.UniqueID("empty-message"), "empty-message"); err != nil {
+		return nil, err
+	}
+	if e.showFooter {
+		if err := e.drawFooter(builder, l); err != nil {
+			return nil, err
+		}
+	}
+	if err := builder.WriteFooter(); err != nil {
+		return nil, err
+	}
+	if err := bw.Flush(); err != nil {
+		return nil, err
+	}
+
+	e.setLastLayout(l.clipRects)
+	return l, nil
+}
+
+// titleOf returns the heading drawn above a timeline.
+func (e *Encoder) titleOf(t *gotio.Timeline) string {
+	if e.title != "" {
+		return e.title
+	}
+	return t.Name()
+}
+
+// newBuilder returns an SVG builder writing to w with the encoder's output
+// settings.
+func (e *Encoder) newBuilder(w io.Writer) *SVGBuilder {
+	builder := NewSVGBuilder(w)
+	builder.SetIDPrefix(e.idPrefix)
+	builder.SetCompact(!e.pretty)
+	builder.SetOmitDeclaration(e.inline)
+	builder.SetResponsive(e.responsive)
+	return builder
+}
+
+// drawBackground fills the canvas with the background color, unless the
+// background is transparent.
+func (e *Encoder) drawBackground(builder *SVGBuilder, l *layout) error {
+	if e.transparentBackground {
+		return nil
+	}
+	background := e.theme.Background
+	if e.background != "" {
+		background = e.background
+	}
+	return builder.WriteRect(0, 0, float64(l.width), float64(l.height), background, "", builder.UniqueID("background"), "background", "")
+}
+
+// hoverCSS emphasizes the clip under the pointer, for SetHoverEffects.
+const hoverCSS = `    .clip:hover {
+      stroke-width: 3;
+      filter: brightness(1.15);
+    }
+`
+
+// boldLabelsCSS draws clip labels bold, for themes with BoldLabels.
+const boldLabelsCSS = `    .clip-label {
+      font-weight: bold;
+    }
+`
+
+// writeStyles writes CSS styles for the SVG.
+func (e *Encoder) writeStyles(builder *SVGBuilder) error {
+	fontFamily := e.fontFamily
+	fontFace := ""
+	if e.font != nil {
+		fontFamily = fmt.Sprintf(`"%s", %s`, e.font.name, fontFamily)
+		fontFace = e.font.fontFaceCSS()
+	}
+
+	extra := ""
+	if e.hoverEffects {
+		extra = hoverCSS
+	}
+	if e.theme.BoldLabels {
+		extra += boldLabelsCSS
+	}
+	stroke := e.theme.strokeWidth()
+
+	css := fmt.Sprintf(`%s
+    text {
+      font-family: %s;
+    }
+    .track-label {
+      font-size: %dpx;
+      fill: %s;
+      font-weight: bold;
+    }
+    .clip-label {
+      font-size: %dpx;
+      fill: %s;
+      pointer-events: none;
+    }
+    .clip-duration {
+      font-size: %dpx;
+      fill: %s;
+      pointer-events: none;
+    }
+    .clip-index {
+      font-weight: bold;
+    }
+    .clip-media {
+      font-size: %dpx;
+      fill: %s;
+      opacity: 0.7;
+      pointer-events: none;
+    }
+    .ruler-text {
+      font-size: %dpx;
+      fill: %s;
+    }
+    .ruler-total {
+      fill: %s;
+      font-weight: bold;
+    }
+    .clip {
+      stroke: %s;
+      stroke-width: %g;
+    }
+    .missing-media {
+      stroke: %s;
+      stroke-width: %g;
+      stroke-dasharray: 6,3;
+    }
+    .gap {
+      stroke: %s;
+      stroke-width: %g;
+      stroke-dasharray: 2,2;
+    }
+    .transition {
+      stroke: %s;
+      stroke-width: %g;
+      fill: none;
+    }
+    .transition-label {
+      font-size: %dpx;
+      fill: %s;
+    }
+    .timeline-title {
+      font-size: %dpx;
+      fill: %s;
+      font-weight: bold;
+    }
+    .legend-text {
+      font-size: %dpx;
+      fill: %s;
+    }
+    .highlight-label {
+      font-size: %dpx;
+      fill: %s;
+      font-weight: bold;
+    }
+    .footer-text {
+      font-size: %dpx;
+      fill: %s;
+    }
+    .empty-message {
+      font-size: %dpx;
+      fill: %s;
+    }
+    .thumbnail {
+      pointer-events: none;
+    }
+    .waveform {
+      opacity: 0.5;
+      pointer-events: none;
+    }
+    .minimap-clip {
+      opacity: 0.8;
+    }
+    .nested-miniature {
+      opacity: 0.6;
+      pointer-events: none;
+    }
+    .track-toggle-glyph {
+      display: none;
+    }
+    .interactive .track-toggle {
+      cursor: pointer;
+    }
+    .interactive .track-toggle-glyph {
+      display: inline;
+    }
+    .handle {
+      opacity: 0.35;
+    }
+    .disabled {
+      opacity: 0.4;
+    }
+    .muted {
+      opacity: 0.5;
+    }
+%s  `, fontFace, fontFamily,
+		e.theme.fontSize(e.trackFontSize), e.theme.Text,
+		e.theme.fontSize(e.clipFontSize), e.theme.ClipText,
+		e.theme.fontSize(e.clipFontSize)-1, e.theme.ClipText,
+		e.theme.fontSize(e.clipFontSize)-1, e.theme.ClipText,
+		e.theme.fontSize(e.rulerFontSize), e.theme.RulerText, e.theme.Text,
+		e.theme.ClipBorder, stroke, e.theme.MissingMedia, 2*stroke, e.theme.GapBorder, stroke, e.theme.Transition, 2*stroke,
+		e.theme.fontSize(noteFontSize), e.theme.Text,
+		e.theme.fontSize(titleFontSize), e.theme.Text,
+		e.theme.fontSize(noteFontSize), e.theme.Text,
+		e.theme.fontSize(noteFontSize), e.theme.Text,
+		e.theme.fontSize(footerFontSize), e.theme.RulerText,
+		e.theme.fontSize(messageFontSize), e.theme.RulerText, extra)
+	return builder.WriteStyle(css)
+}
+
+// laneTracks returns the tracks to draw as lanes, top to bottom.
+func (e *Encoder) laneTracks(children []gotio.Composable) []*gotio.Track {
+	var lanes []*gotio.Track
+	for _, child := range children {
+		track, ok := child.(*gotio.Track)
+		if !ok {
+			continue
+		}
+		if e.skipDisabledTracks && !track.Enabled() {
+			continue
+		}
+		if len(e.trackKinds) > 0 && !slices.Contains(e.trackKinds, track.Kind()) {
+			continue
+		}
+		lanes = append(lanes, track)
+	}
+
+	if e.reverseTracks {
+		slices.Reverse(lanes)
+	}
+	if e.groupByKind {
+		slices.SortStableFunc(lanes, func(a, b *gotio.Track) int {
+			return kindGroup(a) - kindGroup(b)
+		})
+	}
+	return lanes
+}
+
+// kindGroup returns the group a track is drawn in when grouping by kind:
+// 0 for video and other kinds, 1 for audio.
+func kindGroup(track *gotio.Track) int {
+	if track.Kind() == gotio.TrackKindAudio {
+		return 1
+	}
+	return 0
+}
+
+// drawKindDivider draws a line between the video and audio groups of lanes,
+// if there are both.
+func (e *Encoder) drawKindDivider(builder *SVGBuilder, l *layout, lanes []*gotio.Track) error {
+	split := slices.IndexFunc(lanes, func(track *gotio.Track) bool {
+		return kindGroup(track) == 1
+	})
+	if split <= 0 {
+		return nil
+	}
+
+	y := l.tracksTop() + float64(split*l.trackHeight)
+	x1, y1 := l.placePoint(l.contentLeft, y)
+	x2, y2 := l.placePoint(l.contentLeft+l.contentWidth, y)
+	return builder.WriteLine(x1, y1, x2, y2, e.theme.Text, 2, "kind-divider")
+}
+
+// drawTrackSeparators draws a line across the content area at each edge
+// between lanes, and above the first and below the last.
+func (e *Encoder) drawTrackSeparators(builder *SVGBuilder, l *layout) error {
+	for i := 0; i <= l.numTracks; i++ {
+		y := l.tracksTop() + float64(i*l.trackHeight)
+		x1, y1 := l.placePoint(l.contentLeft, y)
+		x2, y2 := l.placePoint(l.contentLeft+l.contentWidth, y)
+		if err := builder.WriteLine(x1, y1, x2, y2, e.theme.Grid, 1, "track-separator"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeDefs defines symbols for gaps and for the track background colors
+// of the theme, and records them in the layout for drawRect to use.
+func (e *Encoder) writeDefs(builder *SVGBuilder, l *layout) error {
+	// Symbols are unit squares scaled to size, which would stretch rounded
+	// corners, so rounded shapes are drawn as plain rectangles instead
+	var symbols []RectSymbol
+	if e.gapCornerRadius == 0 {
+		symbols = append(symbols, RectSymbol{ID: builder.UniqueID("gap-shape"), Fill: e.theme.Gap, Stroke: e.theme.GapBorder})
+	}
+	if e.trackCornerRadius == 0 {
+		alphas := []string{"33"}
+		if e.zebraStripes {
+			alphas = append(alphas, zebraAlpha)
+		}
+		for _, color := range []string{e.theme.VideoTrack, e.theme.AudioTrack} {
+			for _, alpha := range alphas {
+				symbols = append(symbols, RectSymbol{ID: builder.UniqueID("track-bg-shape"), Fill: color + alpha, Stroke: e.theme.Grid})
+			}
+		}
+	}
+	if len(symbols) == 0 {
+		return nil
+	}
+
+	l.symbols = make(map[rectStyle]string, len(symbols))
+	for _, sym := range symbols {
+		l.symbols[rectStyle{sym.Fill, sym.Stroke}] = sym.ID
+	}
+	return builder.WriteDefs(symbols)
+}
+
+// drawRect draws a rectangle with the given corner radius, as a reference
+// to a shared symbol if one has been defined for its fill and stroke.
+func (e *Encoder) drawRect(builder *SVGBuilder, l *layout, x, y, width, height, radius float64, fill, stroke, id, class, title string) error {
+	if symbolID, ok := l.symbols[rectStyle{fill, stroke}]; ok {
+		return builder.WriteUse(symbolID, x, y, width, height, id, class, title)
+	}
+	return builder.WriteRoundedRect(x, y, width, height, radius, radius, fill, stroke, id, class, title, nil)
+}
+
+// drawTimeRuler draws the time ruler at the top. Labels are offset by the
+// timeline's global start time.
+func (e *Encoder) drawTimeRuler(builder *SVGBuilder, l *layout) error {
+	if l.vertical {
+		return e.drawVerticalRuler(builder, l)
+	}
+	if err := builder.StartGroup(builder.UniqueID("time-ruler"), "ruler"); err != nil {
+		return err
+	}
+
+	// Draw ruler background
+	rulerY := l.rulerY()
+	if err := builder.WriteRect(float64(e.marginLeft), rulerY, l.contentWidth, float64(l.rulerHeight), e.theme.TrackLabelBg, e.theme.Grid, "", "ruler-bg", ""); err != nil {
+		return err
+	}
+	if l.dualRuler {
+		dividerY := rulerY + float64(l.rulerHeight)/2
+		if err := builder.WriteLine(float64(e.marginLeft), dividerY, float64(e.marginLeft)+l.contentWidth, dividerY, e.theme.Grid, 1, "ruler-divider"); err != nil {
+			return err
+		}
+	}
+
+	// Draw time markers, keeping the top row of labels clear of the total
+	// duration
+	rulerRight := float64(e.marginLeft) + l.contentWidth
+	totalLabel, totalLeft := "", rulerRight
+	if e.showTotal {
+		totalLabel = formatTimeAs(l.durationSeconds, e.timeFormat, l.rate)
+		totalLeft = rulerRight - estimateTextWidth(totalLabel, float64(e.theme.fontSize(e.rulerFontSize))) - 2*labelPadding
+	}
+	for _, time := range e.rulerTicks(l) {
+		x := float64(e.marginLeft) + time*l.timeScale
+
+		// Draw tick mark
+		if err := builder.WriteLine(x, rulerY, x, rulerY+float64(l.rulerHeight), e.theme.Grid, 1, "tick"); err != nil {
+			return err
+		}
+
+		// Draw time label, right-aligned to the tick if a centered label
+		// would spill past the end of the ruler. A dual ruler labels each
+		// tick with timecode above the time elapsed since the start, or
+		// above the bar number on a bar grid.
+		labels := []string{e.rulerLabel(l, time)}
+		if l.dualRuler {
+			elapsed := formatTime(l.viewStart + time)
+			if e.barGrid(l) {
+				elapsed = e.barLabel(l, time)
+			}
+			labels = []string{e.tickLabel(l, time, TimeFormatTimecode), elapsed}
+		}
+		rowHeight := float64(l.rulerHeight) / float64(len(labels))
+		for row, timeLabel := range labels {
+			if timeLabel == "" {
+				continue
+			}
+			anchor, right := "middle", x+estimateTextWidth(timeLabel, float64(e.theme.fontSize(e.rulerFontSize)))/2
+			if right > rulerRight {
+				anchor, right = "end", x
+			}
+			if row == 0 && totalLabel != "" && right > totalLeft-labelPadding {
+				continue
+			}
+			if err := builder.WriteText(x, rulerY+rowHeight*(float64(row)+0.5), timeLabel, anchor, "", "ruler-text"); err != nil {
+				return err
+			}
+		}
+	}
+
+	// Draw shorter, unlabeled minor ticks between the major ones
+	if minors := e.minorTicks(l); len(minors) > 0 {
+		minorTop := rulerY + float64(l.rulerHeight)*0.75
+		for _, time := range minors {
+			x := float64(e.marginLeft) + time*l.timeScale
+			if err := builder.WriteLine(x, minorTop, x, rulerY+float64(l.rulerHeight), e.theme.Grid, 1, "minor-tick"); err != nil {
+				return err
+			}
+		}
+	}
+
+	if totalLabel != "" {
+		rowHeight := float64(l.rulerHeight)
+		if l.dualRuler {
+			rowHeight /= 2
+		}
+		if err := e.drawTotalDuration(builder, totalLabel, totalLeft, rulerY, rulerRight-totalLeft, rowHeight); err != nil {
+			return err
+		}
+	}
+
+	return builder.EndGroup()
+}
+
+// rulerInterval returns the time between ruler ticks in seconds. Frame
+// labels use whole-frame intervals so every tick lands on a frame boundary.
+func (e *Encoder) rulerInterval(l *layout) float64 {
+	if interval, ok := e.barInterval(l); ok {
+		return interval
+	}
+	// Zooming in shows more detail, as if the timeline were shorter
+	span := l.durationSeconds
+	if l.zoom > 0 {
+		span /= l.zoom
+	}
+	marks := e.rulerTargetMarks(l)
+	if e.frameLabels(l) {
+		return float64(calculateFrameInterval(span*l.rate, l.rate, marks)) / l.rate
+	}
+	return calculateTimeInterval(span, marks)
+}
+
+// rulerTargetMarks returns the number of ruler marks to aim for across the
+// canvas width: the number set, but no more than fit with
+// minRulerMarkSpacing between them.
+func (e *Encoder) rulerTargetMarks(l *layout) float64 {
+	marks := float64(e.rulerMarks)
+	length := l.contentWidth
+	if l.zoom > 0 {
+		length /= l.zoom
+	}
+	if length > 0 {
+		marks = max(min(marks, length/minRulerMarkSpacing), 1)
+	}
+	return marks
+}
+
+// frameLabels reports whether ruler labels count whole frames, as frame
+// numbers, timecode or a dual ruler, so ticks must land on frame
+// boundaries. Otherwise timecode ticks at round seconds of a 29.97 timeline
+// would fall between frames and be labeled with the nearest one.
+func (e *Encoder) frameLabels(l *layout) bool {
+	return (e.timeFormat == TimeFormatFrames || e.timeFormat == TimeFormatTimecode || l.dualRuler) && l.rate > 0
+}
+
+// minorTickDivisions returns how many parts each ruler interval is divided
+// into by minor ticks, preferring fifths. With frame labels, minor ticks
+// must also land on whole frames; 1 means no minor ticks.
+func (e *Encoder) minorTickDivisions(l *layout) int {
+	if e.barGrid(l) {
+		return e.beatDivisions(l)
+	}
+	if !e.frameLabels(l) {
+		return 5
+	}
+	frames := int(math.Round(e.rulerInterval(l) * l.rate))
+	for _, divisions := range []int{5, 4, 2} {
+		if frames%divisions == 0 {
+			return divisions
+		}
+	}
+	return 1
+}
+
+// rulerTicks returns the times, in seconds from the start of the timeline,
+// of the ruler's tick marks.
+func (e *Encoder) rulerTicks(l *layout) []float64 {
+	interval := e.rulerInterval(l)
+	first := e.rulerPhase(l, interval)
+	var ticks []float64
+	for i := 0; first+float64(i)*interval <= l.durationSeconds; i++ {
+		ticks = append(ticks, first+float64(i)*interval)
+	}
+	return ticks
+}
+
+// minorTicks returns the times, in seconds from the start of the ruler, of
+// the minor ticks between the major ones.
+func (e *Encoder) minorTicks(l *layout) []float64 {
+	divisions := e.minorTickDivisions(l)
+	if divisions <= 1 {
+		return nil
+	}
+	interval := e.rulerInterval(l)
+	minor := interval / float64(divisions)
+	first, major := e.rulerPhase(l, minor), e.rulerPhase(l, interval)
+	var ticks []float64
+	for i := 0; first+float64(i)*minor <= l.durationSeconds; i++ {
+		time := first + float64(i)*minor
+		if int(math.Round((time-major)/minor))%divisions == 0 {
+			continue
+		}
+		ticks = append(ticks, time)
+	}
+	return ticks
+}
+
+// rulerPhase returns the time of the first tick at the given interval, in
+// seconds from the start of the ruler. Ticks start with the ruler, except
+// on a musical grid, where they stay on the bars and beats of the whole
+// timeline when a view range crops it.
+func (e *Encoder) rulerPhase(l *layout, interval float64) float64 {
+	if l.viewStart <= 0 || !e.barGrid(l) {
+		return 0
+	}
+	return max(math.Ceil(l.viewStart/interval-1e-9)*interval-l.viewStart, 0)
+}
+
+// drawGrid draws grid lines across the track area at each ruler tick, and
+// fainter ones at each minor tick of a musical grid.
+func (e *Encoder) drawGrid(builder *SVGBuilder, l *layout) error {
+	if err := builder.StartGroup(builder.UniqueID("grid"), "grid"); err != nil {
+		return err
+	}
+
+	if e.barGrid(l) {
+		for _, time := range e.minorTicks(l) {
+			x := l.contentLeft + time*l.timeScale
+			x1, y1 := l.placePoint(x, l.tracksTop())
+			x2, y2 := l.placePoint(x, l.tracksBottom())
+			if err := builder.WriteLine(x1, y1, x2, y2, e.theme.Grid, 0.25, "grid-line grid-minor"); err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, time := range e.rulerTicks(l) {
+		x := l.contentLeft + time*l.timeScale
+		x1, y1 := l.placePoint(x, l.tracksTop())
+		x2, y2 := l.placePoint(x, l.tracksBottom())
+		if err := builder.WriteLine(x1, y1, x2, y2, e.theme.Grid, 0.5, "grid-line"); err != nil {
+			return err
+		}
+	}
+
+	return builder.EndGroup()
+}
+
+// drawPlayhead draws a vertical line at the playhead time spanning the ruler
+// and all tracks, with a triangle marker at the top of the ruler.
+func (e *Encoder) drawPlayhead(builder *SVGBuilder, l *layout, playhead opentime.RationalTime) error {
+	x := l.originX + l.timeOffset(playhead)
+	if x < l.contentLeft || x > l.contentLeft+l.contentWidth {
+		return nil
+	}
+
+	if err := builder.StartGroup(builder.UniqueID("playhead"), "playhead"); err != nil {
+		return err
+	}
+
+	// Across the columns from the ruler, pointing in from its left edge
+	if l.vertical {
+		y := x
+		rulerX := e.verticalRulerX(l)
+		if err := builder.WriteLine(rulerX, y, l.tracksBottom(), y, e.theme.Playhead, 2, "playhead-line"); err != nil {
+			return err
+		}
+		triangle := fmt.Sprintf("M %.2f %.2f L %.2f %.2f L %.2f %.2f Z", rulerX, y-6, rulerX, y+6, rulerX+8, y)
+		if err := builder.WritePath(triangle, e.theme.Playhead, "", 0, "playhead-marker"); err != nil {
+			return err
+		}
+		return builder.EndGroup()
+	}
+
+	rulerY := l.rulerY()
+	if err := builder.WriteLine(x, rulerY, x, l.tracksBottom(), e.theme.Playhead, 2, "playhead-line"); err != nil {
+		return err
+	}
+
+	triangle := fmt.Sprintf("M %.2f %.2f L %.2f %.2f L %.2f %.2f Z", x-6, rulerY, x+6, rulerY, x, rulerY+8)
+	if err := builder.WritePath(triangle, e.theme.Playhead, "", 0, "playhead-marker"); err != nil {
+		return err
+	}
+
+	return builder.EndGroup()
+}
+
+// drawHighlights draws the highlight overlays.
+func (e *Encoder) drawHighlights(builder *SVGBuilder, l *layout) error {
+	if err := builder.StartGroup(builder.UniqueID("highlights"), "highlights"); err != nil {
+		return err
+	}
+
+	left := l.contentLeft
+	right := left + l.contentWidth
+	tracksTop := l.tracksTop()
+	tracksBottom := l.tracksBottom()
+
+	for _, h := range e.highlights {
+		x1 := math.Max(l.originX+l.timeOffset(h.timeRange.StartTime()), left)
+		x2 := math.Min(l.originX+l.timeOffset(h.timeRange.EndTimeExclusive()), right)
+		if x2 <= x1 {
+			continue
+		}
+
+		fill := e.theme.Highlight + "4D" // Add alpha
+		x, y, width, height := l.place(x1, tracksTop, x2-x1, tracksBottom-tracksTop)
+		if err := builder.WriteRect(x, y, width, height, fill, e.theme.Highlight, "", "highlight", ""); err != nil {
+			return err
+		}
+
+		// Labels go above the tracks, or inside the top of vertical ones
+		if h.label != "" {
+			labelX, labelY := x1+2, tracksTop-6
+			if l.vertical {
+				labelX, labelY = tracksTop+2, x1+8
+			}
+			if err := builder.WriteText(labelX, labelY, h.label, "start", "", "highlight-label"); err != nil {
+				return err
+			}
+		}
+	}
+
+	return builder.EndGroup()
+}
+
+// drawTrack draws a single track.
+func (e *Encoder) drawTrack(builder *SVGBuilder, l *layout, track *gotio.Track, index int, yOffset, height float64) error {
+	trackID := builder.UniqueID(fmt.Sprintf("track-%s", sanitizeID(track.Name())))
+	class := "track"
+	if !track.Enabled() {
+		class = "track disabled"
+	}
+	if err := builder.StartGroup(trackID, class); err != nil {
+		return err
+	}
+
+	bgX, bgY, bgWidth, bgHeight := l.place(l.contentLeft, yOffset, l.contentWidth, height)
+	if l.doc != nil {
+		l.doc.Tracks = append(l.doc.Tracks, TrackLayout{
+			ID:    trackID,
+			Name:  track.Name(),
+			Kind:  track.Kind(),
+			Rect:  Rect{X: bgX, Y: bgY, Width: bgWidth, Height: bgHeight},
+			Items: []ItemLayout{},
+		})
+	}
+
+	// Draw track background
+	trackColor := e.trackColor(track)
+
+	// Track background with slight transparency
+	bgColor := trackColor + "33" // Add alpha
+	if e.zebraStripes && index%2 == 1 {
+		// Alternate rows get a lighter tint to separate adjacent lanes
+		bgColor = trackColor + zebraAlpha
+	}
+	if err := e.drawRect(builder, l, bgX, bgY, bgWidth, bgHeight, e.trackCornerRadius, bgColor, e.theme.Grid, "", "track-bg", ""); err != nil {
+		return err
+	}
+
+	// Draw track label, left of the track or centered above its column
+	labelText := track.Name()
+	if labelText == "" {
+		labelText = fmt.Sprintf("%s Track", track.Kind())
+	}
+	labelX, labelY, anchor := float64(e.marginLeft-10), yOffset+height/2, "end"
+	if l.vertical {
+		labelText = truncateText(labelText, height-2*labelPadding, float64(e.theme.fontSize(e.trackFontSize)))
+		labelX, labelY, anchor = yOffset+height/2, l.rulerY()+float64(l.rulerHeight)/2, "middle"
+	}
+	labelClass := "track-label"
+	var labelAttrs []Attr
+	if e.interactive {
+		labelClass += " track-toggle"
+		labelAttrs = trackToggleAttrs(labelText)
+	}
+	if err := builder.WriteTextWithAttrs(labelX, labelY, labelText, anchor, "", labelClass, "", labelAttrs); err != nil {
+		return err
+	}
+	muted, locked := e.trackState(track)
+	labelEnd := labelX
+	if anchor == "middle" {
+		labelEnd += estimateTextWidth(labelText, float64(e.theme.fontSize(e.trackFontSize))) / 2
+	}
+	if err := e.drawTrackState(builder, labelText, labelEnd, labelY, muted, locked); err != nil {
+		return err
+	}
+
+	// Collapsing the track hides the group of its items, toggled by a glyph
+	// in the gap after the label, or after the label of a column
+	if e.interactive {
+		glyphX := float64(e.marginLeft) - 5
+		if l.vertical {
+			glyphX = labelEnd + labelPadding + float64(e.theme.fontSize(e.trackFontSize))/2
+		}
+		if err := drawTrackToggle(builder, glyphX, labelY, labelText); err != nil {
+			return err
+		}
+		if err := builder.StartGroup("", "track-items"); err != nil {
+			return err
+		}
+	}
+
+	// Muted tracks have their items dimmed
+	if muted {
+		if err := builder.StartGroup("", "muted"); err != nil {
+			return err
+		}
+	}
+
+	// Draw items in the track, clipped to the view range if cropped
+	if l.viewClipID != "" {
+		if err := builder.StartClippedGroup(l.viewClipID); err != nil {
+			return err
+		}
+	}
+	if err := e.drawTrackItems(builder, l, track, l.originX, yOffset, height, trackColor); err != nil {
+		return err
+	}
+	if l.viewClipID != "" {
+		if err := builder.EndGroup(); err != nil {
+			return err
+		}
+	}
+	if muted {
+		if err := builder.EndGroup(); err != nil {
+			return err
+		}
+	}
+	if e.interactive {
+		if err := builder.EndGroup(); err != nil {
+			return err
+		}
+	}
+
+	return builder.EndGroup()
 }
 
-// NewEncoder creates a new SVG encoder.
-func NewEncoder(w io.Writer) *Encoder {
-	return &Encoder{
-		w:      w,
-		width:  DefaultWidth,
-		height: DefaultHeight,
+// trackColor returns the color stored in a track's metadata, or else the
+// theme color for its kind.
+func (e *Encoder) trackColor(track *gotio.Track) string {
+	if e.trackColorKey != "" {
+		if color, ok := metadataColor(track.Metadata(), e.trackColorKey); ok {
+			return color
+		}
+	}
+	if track.Kind() == gotio.TrackKindAudio {
+		return e.theme.AudioTrack
 	}
+	return e.theme.VideoTrack
 }
 
-// SetSize sets the SVG canvas size.
-func (e *Encoder) SetSize(width, height int) {
-	e.width = width
-	e.height = height
-}
+// drawTrackItems draws the children of a track. originX is the x position of
+// the track's time zero.
+func (e *Encoder) drawTrackItems(builder *SVGBuilder, l *layout, track *gotio.Track, originX, yOffset, height float64, trackColor string) error {
+	trackName := sanitizeID(track.Name())
+	var cursor opentime.RationalTime
+	var handles []clipHandle
+	clipIndex := 0
+	for i, child := range track.Children() {
+		if i%ctxCheckInterval == 0 {
+			if err := l.ctx.Err(); err != nil {
+				return err
+			}
+		}
 
-// Encode encodes a timeline to SVG.
-func (e *Encoder) Encode(t *gotio.Timeline) error {
-	if t == nil {
-		return fmt.Errorf("timeline is nil")
-	}
+		rng, ok := childRange(track, i, cursor)
+		if !ok {
+			continue
+		}
+		rng = forwardRange(rng)
+		if _, ok := child.(*gotio.Transition); !ok {
+			cursor = rng.EndTimeExclusive()
+		}
 
-	builder := NewSVGBuilder(e.w)
+		x := originX + l.timeOffset(rng.StartTime())
+		if !finite(x) {
+			continue
+		}
+		width := e.itemWidth(originX + l.timeOffset(rng.EndTimeExclusive()) - x)
+		if l.viewClipID != "" && (x+width <= l.contentLeft || x >= l.contentLeft+l.contentWidth) {
+			continue
+		}
+		px, py, pw, ph := l.placeItem(x, yOffset, width, height)
 
-	// Write SVG header
-	if err := builder.WriteHeader(e.width, e.height); err != nil {
-		return err
-	}
+		// Disabled items keep their place but are dimmed
+		disabled := !isEnabled(child)
+		if disabled {
+			if err := builder.StartGroup("", "disabled"); err != nil {
+				return err
+			}
+		}
 
-	// Write CSS styles
-	if err := e.writeStyles(builder); err != nil {
-		return err
-	}
+		switch item := child.(type) {
+		case *gotio.Clip:
+			l.stats.Clips++
+			clipIndex++
+			index := clipIndex
+			if e.clipIndexAll {
+				index = i + 1
+			}
+			id := builder.UniqueID(fmt.Sprintf("clip-%s", sanitizeID(item.Name())))
+			l.recordItem(id, "clip", item.Name(), rng, px, py, pw, ph)
+			if err := e.drawClip(builder, l, item, id, index, track.Kind(), rng, px, py, pw, ph, trackColor); err != nil {
+				return err
+			}
+			if e.showHandles {
+				handles = append(handles, e.handlesOf(l, item, x, yOffset, width, height)...)
+			}
 
-	// Get timeline duration
-	duration, err := t.Duration()
-	if err != nil {
-		return fmt.Errorf("failed to get timeline duration: %w", err)
-	}
+		case *gotio.Gap:
+			l.stats.Gaps++
+			id := builder.UniqueID(fmt.Sprintf("gap-%s-%d", trackName, i))
+			l.recordItem(id, "gap", item.Name(), rng, px, py, pw, ph)
+			if err := e.drawGap(builder, l, item, id, px, py, pw, ph); err != nil {
+				return err
+			}
 
-	if duration.Value() <= 0 {
-		return fmt.Errorf("timeline has no duration")
-	}
+		case *gotio.Transition:
+			l.stats.Transitions++
+			id := builder.UniqueID(fmt.Sprintf("transition-%s-%d", trackName, i))
+			l.recordItem(id, "transition", item.Name(), rng, px, py, pw, ph)
+			if err := e.drawTransition(builder, item, id, px, py, pw, ph); err != nil {
+				return err
+			}
 
-	// Calculate content area
-	contentWidth := float64(e.width - MarginLeft - MarginRight)
-	contentHeight := float64(e.height - MarginTop - MarginBottom)
+		case *gotio.Stack:
+			id := builder.UniqueID(fmt.Sprintf("stack-%s-%d", trackName, i))
+			l.recordItem(id, "stack", item.Name(), rng, px, py, pw, ph)
+			if err := e.drawStack(builder, l, item, id, x, yOffset, width, height, trackColor); err != nil {
+				return err
+			}
+		}
 
-	// Get all tracks
-	tracks := t.Tracks()
-	if tracks == nil {
-		return fmt.Errorf("timeline has no tracks")
+		if disabled {
+			if err := builder.EndGroup(); err != nil {
+				return err
+			}
+		}
 	}
 
-	allTracks := tracks.Children()
-	numTracks := len(allTracks)
-	if numTracks == 0 {
-		return fmt.Errorf("timeline has no tracks")
-	}
+	return e.drawHandles(builder, l, handles, trackColor)
+}
 
-	// Calculate scale: pixels per second
-	durationSeconds := duration.ToSeconds()
-	timeScale := contentWidth / durationSeconds
+// itemWidth returns the width of an item spanning length pixels along the
+// time axis, at least the minimum clip width. Lengths that are negative or
+// not finite, as messy files can have, get the minimum width.
+func (e *Encoder) itemWidth(length float64) float64 {
+	width := length
+	if !(width >= e.minClipWidth) || math.IsInf(width, 0) {
+		return e.minClipWidth
+	}
+	return width
+}
 
-	// Draw time ruler at top
-	if err := e.drawTimeRuler(builder, duration, timeScale); err != nil {
-		return err
+// childRange returns the range of the child at index i of a track. The
+// track is authoritative for where each child sits, including transitions,
+// which straddle the cut between their neighbours. If the track can't place
+// the child, because it or an earlier item has no duration, the child is
+// placed at cursor, the end of the last item placed, with clips falling back
+// to the available range of their media. ok is false if the child has no
+// duration at all.
+func childRange(track *gotio.Track, i int, cursor opentime.RationalTime) (opentime.TimeRange, bool) {
+	if rng, err := track.RangeOfChildAtIndex(i); err == nil {
+		return rng, true
 	}
 
-	// Draw each track
-	trackHeight := TrackHeight
-	if numTracks > 0 {
-		availableHeight := contentHeight - RulerHeight
-		trackHeight = int(availableHeight / float64(numTracks))
-		if trackHeight > TrackHeight {
-			trackHeight = TrackHeight
-		}
-		if trackHeight < 40 {
-			trackHeight = 40
+	child := track.Children()[i]
+	if transition, ok := child.(*gotio.Transition); ok {
+		inOffset := transition.InOffset()
+		if cursor.Rate() == 0 {
+			cursor = opentime.NewRationalTime(0, inOffset.Rate())
 		}
+		return opentime.NewTimeRange(cursor.Sub(inOffset), inOffset.Add(transition.OutOffset())), true
 	}
 
-	for i, child := range allTracks {
-		track, ok := child.(*gotio.Track)
+	duration, err := child.Duration()
+	if err != nil {
+		clip, ok := child.(*gotio.Clip)
 		if !ok {
-			continue
+			return opentime.TimeRange{}, false
 		}
-
-		yOffset := MarginTop + RulerHeight + float64(i*trackHeight)
-		if err := e.drawTrack(builder, track, yOffset, float64(trackHeight), timeScale); err != nil {
-			return err
+		available, err := clip.AvailableRange()
+		if err != nil {
+			return opentime.TimeRange{}, false
 		}
+		duration = available.Duration()
 	}
 
-	// Write SVG footer
-	if err := builder.WriteFooter(); err != nil {
-		return err
+	if cursor.Rate() == 0 {
+		cursor = opentime.NewRationalTime(0, duration.Rate())
 	}
-
-	return nil
+	return opentime.NewTimeRange(cursor, duration), true
 }
 
-// writeStyles writes CSS styles for the SVG.
-func (e *Encoder) writeStyles(builder *SVGBuilder) error {
-	css := `
-    .track-label {
-      font-family: Arial, sans-serif;
-      font-size: 12px;
-      fill: #333;
-      font-weight: bold;
-    }
-    .clip-label {
-      font-family: Arial, sans-serif;
-      font-size: 10px;
-      fill: white;
-      pointer-events: none;
-    }
-    .ruler-text {
-      font-family: Arial, sans-serif;
-      font-size: 10px;
-      fill: #666;
-    }
-    .clip {
-      stroke: #333;
-      stroke-width: 1;
-    }
-    .gap {
-      stroke: #999;
-      stroke-width: 1;
-      stroke-dasharray: 2,2;
-    }
-    .transition {
-      stroke: #333;
-      stroke-width: 2;
-      fill: none;
-    }
-  `
-	return builder.WriteStyle(css)
+// isEnabled reports whether a composable is enabled. Composables without an
+// enabled flag, such as transitions, are always enabled.
+func isEnabled(c gotio.Composable) bool {
+	if item, ok := c.(interface{ Enabled() bool }); ok {
+		return item.Enabled()
+	}
+	return true
 }
 
-// drawTimeRuler draws the time ruler at the top.
-func (e *Encoder) drawTimeRuler(builder *SVGBuilder, duration opentime.RationalTime, timeScale float64) error {
-	if err := builder.StartGroup("time-ruler", "ruler"); err != nil {
+// drawStack draws a stack nested in a track, rendering each of its tracks
+// as a sub-lane within the parent track's band. Unlike the other items, it
+// takes its band in layout coordinates, for laying out the sub-lanes.
+func (e *Encoder) drawStack(builder *SVGBuilder, l *layout, stack *gotio.Stack, stackID string, x, y, width, height float64, trackColor string) error {
+	if err := builder.StartGroup(stackID, "stack"); err != nil {
 		return err
 	}
 
-	// Draw ruler background
-	rulerY := float64(MarginTop)
-	rulerWidth := float64(e.width - MarginLeft - MarginRight)
-	if err := builder.WriteRect(float64(MarginLeft), rulerY, rulerWidth, RulerHeight, TrackLabelBg, GridColor, "", "ruler-bg", ""); err != nil {
+	padding := 2.0
+	stackY := y + padding
+	stackHeight := height - 2*padding
+
+	bgX, bgY, bgWidth, bgHeight := l.placeItem(x, y, width, height)
+	if err := builder.WriteRect(bgX, bgY+padding, bgWidth, bgHeight-2*padding, e.theme.TrackLabelBg, e.theme.Grid, "", "stack-bg", ""); err != nil {
 		return err
 	}
 
-	// Draw time markers
-	durationSeconds := duration.ToSeconds()
+	var tracks []*gotio.Track
+	for _, child := range stack.Children() {
+		if track, ok := child.(*gotio.Track); ok {
+			tracks = append(tracks, track)
+		}
+	}
 
-	// Calculate appropriate interval
-	interval := calculateTimeInterval(durationSeconds)
+	if len(tracks) > 0 {
+		// The stack's source range trims its children, so shift their
+		// time zero to line up the trimmed start with the stack's x.
+		originX := x
+		if sr := stack.SourceRange(); sr != nil {
+			originX -= l.timeOffset(sr.StartTime())
+		}
 
-	time := 0.0
-	for time <= durationSeconds {
-		x := float64(MarginLeft) + time*timeScale
+		laneHeight := (stackHeight - 2*padding) / float64(len(tracks))
+		for i, track := range tracks {
+			laneY := stackY + padding + float64(i)*laneHeight
+			laneColor := trackColor
+			if track.Kind() != "" {
+				laneColor = e.trackColor(track)
+			}
+			if err := e.drawTrackItems(builder, l, track, originX, laneY, laneHeight, laneColor); err != nil {
+				return err
+			}
+		}
+	}
 
-		// Draw tick mark
-		if err := builder.WriteLine(x, rulerY, x, rulerY+RulerHeight, GridColor, 1, "tick"); err != nil {
+	return builder.EndGroup()
+}
+
+// drawClip draws a clip, the index-th within its track.
+func (e *Encoder) drawClip(builder *SVGBuilder, l *layout, clip *gotio.Clip, clipID string, index int, trackKind string, rng opentime.TimeRange, x, y, width, height float64, trackColor string) error {
+	// Adjust clip rectangle to have some padding
+	padding := 2.0
+	clipY := y + padding
+	clipHeight := height - 2*padding
+
+	if l.clipRects != nil {
+		l.clipRects[clipID] = Rect{X: x, Y: clipY, Width: width, Height: clipHeight}
+	}
+
+	// Draw clip rectangle, linked to its media if it has a URL
+	url := mediaURL(clip)
+	if url != "" {
+		if err := builder.StartAnchorWithLabel(url, "Open media for "+clipName(clip)); err != nil {
+			return err
+		}
+	}
+	if e.clipColor != nil {
+		if fill := e.clipColor(clip); fill != "" {
+			trackColor = fill
+		}
+	}
+	class := "clip"
+	if e.diff != nil {
+		if fill, diffClass, ok := e.diff.clipStyle(clip); ok {
+			trackColor = fill
+			class += " " + diffClass
+		}
+	}
+	data := append(clipDataAttrs(clip, rng, e.dataNamespace), Attr{"aria-label", e.clipAriaLabel(l, clip, rng)})
+	if err := builder.WriteRoundedRect(x, clipY, width, clipHeight, e.clipCornerRadius, e.clipCornerRadius, trackColor, e.theme.ClipBorder, clipID, class, clipTitle(clip), data); err != nil {
+		return err
+	}
+	if url != "" {
+		if err := builder.EndAnchor(); err != nil {
 			return err
 		}
+	}
 
-		// Draw time label
-		timeLabel := formatTime(time)
-		if err := builder.WriteText(x, rulerY+RulerHeight/2, timeLabel, "middle", "", "ruler-text"); err != nil {
+	if e.thumbnailProvider != nil && trackKind == gotio.TrackKindVideo {
+		if err := e.drawThumbnail(builder, clip, x, clipY, width, clipHeight); err != nil {
+			return err
+		}
+	}
+	if (e.audioWaveform || e.waveformProvider != nil) && trackKind == gotio.TrackKindAudio {
+		if err := e.drawWaveform(builder, clip, rng, x, clipY, width, clipHeight, trackColor); err != nil {
 			return err
 		}
+	}
 
-		time += interval
+	// Mark clips widened to the minimum width, so they aren't mistaken for
+	// clips that really last that long
+	if length := l.timeOffset(rng.EndTimeExclusive()) - l.timeOffset(rng.StartTime()); !(length >= e.minClipWidth) {
+		if err := builder.WriteRectWithTitle(x, clipY, width, minWidthAccentHeight, e.theme.Highlight, "", "", "min-width", "Shorter than shown"); err != nil {
+			return err
+		}
 	}
 
-	return builder.EndGroup()
-}
+	// Outline clips with missing media so they stand out
+	if hasMissingMedia(clip) {
+		if err := builder.WriteRoundedRect(x, clipY, width, clipHeight, e.clipCornerRadius, e.clipCornerRadius, "none", e.theme.MissingMedia, "", "missing-media", "Media missing", nil); err != nil {
+			return err
+		}
+	}
 
-// drawTrack draws a single track.
-func (e *Encoder) drawTrack(builder *SVGBuilder, track *gotio.Track, yOffset, height, timeScale float64) error {
-	trackID := fmt.Sprintf("track-%s", sanitizeID(track.Name()))
-	if err := builder.StartGroup(trackID, "track"); err != nil {
+	// Point an arrow backwards on clips that play in reverse, and badge
+	// clips referencing nested timelines, with a miniature if enabled
+	markColor := contrastColor(trackColor)
+	if markColor == "" {
+		markColor = e.theme.ClipText
+	}
+	reversed := isReversed(clip)
+	if reversed {
+		if err := e.drawReverseMarker(builder, l, x, clipY, width, clipHeight, markColor); err != nil {
+			return err
+		}
+	}
+	if err := e.drawNested(builder, l, clip, x, clipY, width, clipHeight, markColor); err != nil {
 		return err
 	}
 
-	// Draw track background
-	trackColor := VideoTrackColor
-	if track.Kind() == gotio.TrackKindAudio {
-		trackColor = AudioTrackColor
+	// Draw the clip's name, with its metadata label, duration and media
+	// name below if enabled
+	clipName := clip.Name()
+	if clipName == "" {
+		clipName = "Clip"
 	}
-
-	// Track background with slight transparency
-	bgColor := trackColor + "33" // Add alpha
-	if err := builder.WriteRect(float64(MarginLeft), yOffset, float64(e.width-MarginLeft-MarginRight), height, bgColor, GridColor, "", "track-bg", ""); err != nil {
-		return err
+	lines := []labelLine{{clipName, "clip-label"}}
+	if text := e.clipMetadataLabel(clip); text != "" {
+		lines = append(lines, labelLine{text, "clip-duration clip-metadata"})
+	}
+	if e.showClipDuration {
+		lines = append(lines, labelLine{formatTimeAs(rng.Duration().ToSeconds(), e.timeFormat, l.rate), "clip-duration"})
+	}
+	if e.showMediaName {
+		if name := mediaName(clip); name != "" {
+			lines = append(lines, labelLine{name, "clip-media"})
+		}
 	}
 
-	// Draw track label
-	labelText := track.Name()
-	if labelText == "" {
-		labelText = fmt.Sprintf("%s Track", track.Kind())
+	// Fit the label, icon and badge to the part of the clip in view
+	shownX, shownWidth := x, width
+	if l.viewClipID != "" && !l.vertical {
+		shownX = math.Max(x, l.contentLeft)
+		shownWidth = math.Min(x+width, l.contentLeft+l.contentWidth) - shownX
 	}
-	if err := builder.WriteText(float64(MarginLeft-10), yOffset+height/2, labelText, "end", "", "track-label"); err != nil {
+
+	labelWidth, err := drawLabelLines(builder, lines, shownX, clipY, shownWidth, clipHeight, float64(e.theme.fontSize(e.clipFontSize)), contrastColor(trackColor))
+	if err != nil {
 		return err
 	}
 
-	// Draw items in the track
-	currentTime := 0.0
-	for _, child := range track.Children() {
-		dur, err := child.Duration()
-		if err != nil {
-			continue
+	if e.showClipIndex {
+		if err := e.drawClipIndex(builder, index, shownX, clipY, shownWidth, clipHeight, labelWidth, len(lines), reversed, contrastColor(trackColor)); err != nil {
+			return err
 		}
+	}
 
-		durSeconds := dur.ToSeconds()
+	// Space on either side of the label for the icon and effect badge
+	side := (shownWidth - labelWidth) / 2
 
-		switch item := child.(type) {
-		case *gotio.Clip:
-			x := float64(MarginLeft) + currentTime*timeScale
-			width := math.Max(durSeconds*timeScale, MinClipWidth)
-			if err := e.drawClip(builder, item, x, yOffset, width, height, trackColor); err != nil {
-				return err
+	// Draw the media type icon in the bottom-left corner if it fits beside
+	// the label
+	if icon := mediaIconFor(clip, trackKind); icon != iconNone {
+		if side >= mediaIconSize+2*labelPadding && clipHeight >= mediaIconSize+2*labelPadding {
+			iconColor := contrastColor(trackColor)
+			if iconColor == "" {
+				iconColor = e.theme.ClipText
 			}
-			if child.Visible() {
-				currentTime += durSeconds
+			d := mediaIconPath(icon, shownX+labelPadding, clipY+clipHeight-labelPadding-mediaIconSize)
+			if err := builder.WritePath(d, "none", iconColor, 1, "media-icon"); err != nil {
+				return err
 			}
+		}
+	}
 
-		case *gotio.Gap:
-			x := float64(MarginLeft) + currentTime*timeScale
-			width := math.Max(durSeconds*timeScale, MinClipWidth)
-			if err := e.drawGap(builder, item, x, yOffset, width, height); err != nil {
+	// Draw the effect badge in the bottom-right corner if it fits beside
+	// the label
+	if badge, title := effectBadge(clip); badge != "" {
+		badgeWidth := estimateTextWidth(badge, float64(e.theme.fontSize(e.clipFontSize))) + 2*labelPadding
+		badgeHeight := math.Max(badgeHeight, float64(e.theme.fontSize(e.clipFontSize)+labelLineGap))
+		if side >= badgeWidth+2*labelPadding && clipHeight >= badgeHeight+2*labelPadding {
+			badgeX := shownX + shownWidth - labelPadding - badgeWidth
+			badgeY := clipY + clipHeight - labelPadding - badgeHeight
+			if err := builder.WriteRectWithTitle(badgeX, badgeY, badgeWidth, badgeHeight, e.theme.ClipBorder, "", "", "effect-badge", title); err != nil {
 				return err
 			}
-			if child.Visible() {
-				currentTime += durSeconds
-			}
-
-		case *gotio.Transition:
-			x := float64(MarginLeft) + currentTime*timeScale
-			width := math.Max(durSeconds*timeScale, MinClipWidth)
-			if err := e.drawTransition(builder, item, x, yOffset, width, height); err != nil {
+			if err := builder.WriteTextWithFill(badgeX+badgeWidth/2, badgeY+badgeHeight/2, badge, "middle", "", "clip-label", e.theme.ClipText); err != nil {
 				return err
 			}
-			// Transitions don't advance time (they overlap)
 		}
 	}
 
-	return builder.EndGroup()
+	// Draw markers on top of the clip
+	if l.vertical {
+		return nil
+	}
+	return e.drawClipMarkers(builder, clip, x, clipY, width, l.timeScale)
 }
 
-// drawClip draws a clip.
-func (e *Encoder) drawClip(builder *SVGBuilder, clip *gotio.Clip, x, y, width, height float64, trackColor string) error {
-	clipID := fmt.Sprintf("clip-%s", sanitizeID(clip.Name()))
-
-	// Adjust clip rectangle to have some padding
-	padding := 2.0
-	clipY := y + padding
-	clipHeight := height - 2*padding
+// labelLine is one line of a multi-line label.
+type labelLine struct {
+	text  string
+	class string
+}
 
-	// Draw clip rectangle
-	if err := builder.WriteRect(x, clipY, width, clipHeight, trackColor, "#333", clipID, "clip", ""); err != nil {
-		return err
+// drawLabelLines draws lines of text of the given font size centered in a
+// rectangle, each truncated to fit its width. Lines that don't fit the
+// height are dropped from the end, but the first line is always drawn. It
+// returns the width of the widest line drawn.
+func drawLabelLines(builder *SVGBuilder, lines []labelLine, x, y, width, height, fontSize float64, fill string) (float64, error) {
+	lineHeight := fontSize + labelLineGap
+	if maxLines := int((height - 2*labelPadding) / lineHeight); len(lines) > maxLines {
+		lines = lines[:max(maxLines, 1)]
 	}
 
-	// Draw clip name if there's room
-	if width > 30 {
-		clipName := clip.Name()
-		if clipName == "" {
-			clipName = "Clip"
-		}
-		textX := x + width/2
-		textY := y + height/2
-		if err := builder.WriteText(textX, textY, clipName, "middle", "", "clip-label"); err != nil {
-			return err
+	widest := 0.0
+	textY := y + height/2 - float64(len(lines)-1)*lineHeight/2
+	for _, line := range lines {
+		text := truncateText(line.text, width-2*labelPadding, fontSize)
+		if text != "" {
+			if err := builder.WriteTextWithFill(x+width/2, textY, text, "middle", "", line.class, fill); err != nil {
+				return 0, err
+			}
+			widest = math.Max(widest, estimateTextWidth(text, fontSize))
 		}
+		textY += lineHeight
 	}
-
-	return nil
+	return widest, nil
 }
 
 // drawGap draws a gap.
-func (e *Encoder) drawGap(builder *SVGBuilder, gap *gotio.Gap, x, y, width, height float64) error {
-	gapID := fmt.Sprintf("gap-%p", gap)
-
+func (e *Encoder) drawGap(builder *SVGBuilder, l *layout, gap *gotio.Gap, gapID string, x, y, width, height float64) error {
 	padding := 2.0
 	gapY := y + padding
 	gapHeight := height - 2*padding
 
 	// Draw gap rectangle with dashed border
-	return builder.WriteRect(x, gapY, width, gapHeight, GapColor, "#999", gapID, "gap", "")
+	if err := e.drawRect(builder, l, x, gapY, width, gapHeight, e.gapCornerRadius, e.theme.Gap, e.theme.GapBorder, gapID, "gap", gapTitle(gap)); err != nil {
+		return err
+	}
+
+	// Draw the gap's duration if it fits
+	dur, err := gap.Duration()
+	if err != nil {
+		return nil
+	}
+	label := formatTimeAs(dur.ToSeconds(), e.timeFormat, l.rate)
+	if estimateTextWidth(label, float64(e.theme.fontSize(e.clipFontSize))) > width-2*labelPadding {
+		return nil
+	}
+	return builder.WriteTextWithFill(x+width/2, y+height/2, label, "middle", "", "clip-label", e.theme.GapText)
 }
 
-// drawTransition draws a transition as a diagonal line.
-func (e *Encoder) drawTransition(builder *SVGBuilder, transition *gotio.Transition, x, y, width, height float64) error {
+// drawTransition draws a transition as a glyph that depends on its type,
+// with its name above the glyph if there's room.
+func (e *Encoder) drawTransition(builder *SVGBuilder, transition *gotio.Transition, transitionID string, x, y, width, height float64) error {
 	padding := 2.0
 	transY := y + padding
 	transHeight := height - 2*padding
 
-	// Draw diagonal line from bottom-left to top-right
-	x1 := x
-	y1 := transY + transHeight
-	x2 := x + width
-	y2 := transY
-
 	// Draw the transition path
-	path := fmt.Sprintf("M %.2f %.2f L %.2f %.2f", x1, y1, x2, y2)
-	return builder.WritePath(path, "none", TransitionColor, 3, "transition")
+	shape := transitionShapeFor(transition)
+	if e.transitionStyle == TransitionStyleCurved {
+		shape = shapeCurves
+	}
+	path := transitionPath(shape, x, transY, width, transHeight)
+	if err := builder.WritePathWithTitle(path, "none", e.theme.Transition, 3, transitionID, "transition", transitionTitle(transition)); err != nil {
+		return err
+	}
+
+	// Draw transition name
+	fontSize := float64(e.theme.fontSize(noteFontSize))
+	label := truncateText(transition.Name(), width, fontSize)
+	if label == "" {
+		return nil
+	}
+	return builder.WriteText(x+width/2, transY+fontSize, label, "middle", "", "transition-label")
+}
+
+// documentTitle returns the document-level title for a timeline.
+func documentTitle(t *gotio.Timeline) string {
+	if t.Name() == "" {
+		return "Timeline"
+	}
+	return t.Name()
+}
+
+// documentDesc summarizes a timeline's tracks, clips, and duration.
+func documentDesc(tracks []gotio.Composable, durationSeconds float64) string {
+	numTracks := 0
+	numClips := 0
+	for _, child := range tracks {
+		if track, ok := child.(*gotio.Track); ok {
+			numTracks++
+			numClips += countClips(track)
+		}
+	}
+	return fmt.Sprintf("%s, %s, duration %s",
+		plural(numTracks, "track"), plural(numClips, "clip"), formatTime(durationSeconds))
+}
+
+// countClips counts the clips in a composition, including clips in nested
+// stacks.
+func countClips(c gotio.Composition) int {
+	n := 0
+	for _, child := range c.Children() {
+		switch item := child.(type) {
+		case *gotio.Clip:
+			n++
+		case gotio.Composition:
+			n += countClips(item)
+		}
+	}
+	return n
+}
+
+// plural formats a count with a singular or plural noun.
+func plural(n int, noun string) string {
+	if n == 1 {
+		return fmt.Sprintf("1 %s", noun)
+	}
+	return fmt.Sprintf("%d %ss", n, noun)
+}
+
+// clipName returns a clip's name, or "Clip" if it has none.
+func clipName(clip *gotio.Clip) string {
+	if clip.Name() == "" {
+		return "Clip"
+	}
+	return clip.Name()
+}
+
+// clipAriaLabel describes a clip for assistive technology by its name and
+// the times it starts and ends on the timeline, e.g. "Shot 1, 2.0s to 4.0s".
+func (e *Encoder) clipAriaLabel(l *layout, clip *gotio.Clip, rng opentime.TimeRange) string {
+	start, end := rng.StartTime().ToSeconds(), rng.EndTimeExclusive().ToSeconds()
+	if !finite(start) || !finite(end) {
+		return clipName(clip)
+	}
+	return fmt.Sprintf("%s, %s to %s", clipName(clip),
+		formatTimeAs(l.startSeconds+start, e.timeFormat, l.rate), formatTimeAs(l.startSeconds+end, e.timeFormat, l.rate))
+}
+
+// clipTitle describes a clip for its tooltip.
+func clipTitle(clip *gotio.Clip) string {
+	lines := []string{clipName(clip)}
+	if dur, err := clip.Duration(); err == nil && finite(dur.ToSeconds()) {
+		lines = append(lines, "Duration: "+formatTime(dur.ToSeconds()))
+	}
+	if sr := clip.SourceRange(); sr != nil {
+		start, end := sr.StartTime().ToSeconds(), sr.EndTimeExclusive().ToSeconds()
+		if finite(start) && finite(end) {
+			lines = append(lines, fmt.Sprintf("Source: %s - %s", formatTime(start), formatTime(end)))
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// mediaURL returns the target URL of a clip's external media reference, or
+// "" if it has none.
+func mediaURL(clip *gotio.Clip) string {
+	if ref, ok := clip.MediaReference().(*gotio.ExternalReference); ok {
+		return ref.TargetURL()
+	}
+	return ""
+}
+
+// mediaName returns the name of a clip's media reference. External
+// references without a name are named after the file in their target URL.
+// Missing references have no name.
+func mediaName(clip *gotio.Clip) string {
+	if hasMissingMedia(clip) {
+		return ""
+	}
+	ref := clip.MediaReference()
+	if name := ref.Name(); name != "" {
+		return name
+	}
+	if ext, ok := ref.(*gotio.ExternalReference); ok && ext.TargetURL() != "" {
+		target := ext.TargetURL()
+		if u, err := url.Parse(target); err == nil && u.Path != "" {
+			target = u.Path
+		}
+		return path.Base(target)
+	}
+	return ""
+}
+
+// hasMissingMedia reports whether a clip has no media reference or a
+// MissingReference.
+func hasMissingMedia(clip *gotio.Clip) bool {
+	switch clip.MediaReference().(type) {
+	case nil, *gotio.MissingReference:
+		return true
+	default:
+		return false
+	}
 }
 
-// calculateTimeInterval calculates an appropriate time interval for ruler marks.
-func calculateTimeInterval(durationSeconds float64) float64 {
-	intervals := []float64{0.1, 0.5, 1, 2, 5, 10, 15, 30, 60, 120, 300, 600, 1800, 3600}
+// gapTitle describes a gap for its tooltip.
+func gapTitle(gap *gotio.Gap) string {
+	dur, err := gap.Duration()
+	if err != nil {
+		return "Gap"
+	}
+	return "Gap\nDuration: " + formatTime(dur.ToSeconds())
+}
+
+// transitionTitle describes a transition for its tooltip.
+func transitionTitle(transition *gotio.Transition) string {
+	name := transition.Name()
+	if name == "" {
+		name = "Transition"
+	}
+	lines := []string{
+		name,
+		"Type: " + transition.TransitionType(),
+	}
+	if dur, err := transition.Duration(); err == nil {
+		lines = append(lines, "Duration: "+formatTime(dur.ToSeconds()))
+	}
+	lines = append(lines,
+		"In: "+formatTime(transition.InOffset().ToSeconds()),
+		"Out: "+formatTime(transition.OutOffset().ToSeconds()))
+	return strings.Join(lines, "\n")
+}
+
+// calculateTimeInterval calculates an appropriate time interval for ruler
+// marks, giving about targetMarks marks over the duration. Intervals range
+// from hundredths of a second, finer than a frame, to a day.
+func calculateTimeInterval(durationSeconds, targetMarks float64) float64 {
+	intervals := []float64{
+		0.01, 0.02, 0.05, 0.1, 0.2, 0.5, 1, 2, 5, 10, 15, 30,
+		60, 120, 300, 600, 1800,
+		3600, 7200, 10800, 21600, 43200, 86400,
+	}
 
-	// Aim for about 10-20 marks
-	targetMarks := 12.0
 	idealInterval := durationSeconds / targetMarks
 
 	// Find closest interval
@@ -390,8 +2531,16 @@ func calculateTimeInterval(durationSeconds float64) float64 {
 	return bestInterval
 }
 
-// formatTime formats seconds as a time string.
+// formatTime formats seconds as a time string. Times under a second keep
+// up to millisecond precision, so fine ruler intervals stay distinct.
 func formatTime(seconds float64) string {
+	if math.Abs(seconds) < 1 {
+		s := strings.TrimRight(fmt.Sprintf("%.3f", seconds), "0")
+		if strings.HasSuffix(s, ".") {
+			s += "0"
+		}
+		return s + "s"
+	}
 	if seconds < 60 {
 		return fmt.Sprintf("%.1fs", seconds)
 	}
@@ -414,23 +2563,21 @@ func sanitizeID(s string) string {
 		return "unnamed"
 	}
 
-	// Replace non-alphanumeric characters with underscores
-	result := ""
+	// Replace non-alphanumeric characters with underscores, prefixing ids
+	// that don't start with a letter
+	var sb strings.Builder
+	sb.Grow(len(s) + 3)
+	first := s[0]
+	if !((first >= 'a' && first <= 'z') || (first >= 'A' && first <= 'Z')) {
+		sb.WriteString("id_")
+	}
 	for _, r := range s {
 		if (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') || r == '_' || r == '-' {
-			result += string(r)
+			sb.WriteRune(r)
 		} else {
-			result += "_"
-		}
-	}
-
-	// Ensure it starts with a letter
-	if len(result) > 0 {
-		first := result[0]
-		if !((first >= 'a' && first <= 'z') || (first >= 'A' && first <= 'Z')) {
-			result = "id_" + result
+			sb.WriteByte('_')
 		}
 	}
 
-	return result
+	return sb.String()
 }
@@ -5,7 +5,18 @@ package svg
 
 import (
 	"bytes"
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
 	"strings"
+	"sync"
 	"testing"
 
 	"github.com/Avalanche-io/gotio"
@@ -389,6 +400,91 @@ func TestEncodeEmptyTimeline(t *testing.T) {
 	}
 }
 
+func TestEncodePathologicalDurations(t *testing.T) {
+	clipOf := func(name string, duration opentime.RationalTime) *gotio.Clip {
+		sr := opentime.NewTimeRange(opentime.NewRationalTime(0, 24), duration)
+		return gotio.NewClip(name, nil, &sr, nil, nil, nil, "", nil)
+	}
+
+	tests := []struct {
+		name  string
+		clips [][]*gotio.Clip // clips of each track
+	}{
+		{"zero duration", [][]*gotio.Clip{{
+			newTestClip("Before", 24, 24),
+			newTestClip("Empty", 0, 24),
+			newTestClip("After", 24, 24),
+		}}},
+		{"negative duration", [][]*gotio.Clip{{
+			newTestClip("Before", 48, 24),
+			clipOf("Negative", opentime.NewRationalTime(-24, 24)),
+		}}},
+		{"NaN duration", [][]*gotio.Clip{
+			{newTestClip("Longest", 48, 24)},
+			{clipOf("Undefined", opentime.NewRationalTime(math.NaN(), 24)), newTestClip("After", 24, 24)},
+		}},
+		{"infinite duration", [][]*gotio.Clip{
+			{newTestClip("Longest", 48, 24)},
+			{clipOf("Endless", opentime.NewRationalTime(math.Inf(-1), 24))},
+		}},
+		{"vanishingly short", [][]*gotio.Clip{{
+			clipOf("Tiny", opentime.NewRationalTime(1, 1e300)),
+		}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			timeline := gotio.NewTimeline("Pathological", nil, nil)
+			for i, clips := range tt.clips {
+				track := gotio.NewTrack(fmt.Sprintf("V%d", i+1), nil, gotio.TrackKindVideo, nil, nil)
+				for _, clip := range clips {
+					if err := track.AppendChild(clip); err != nil {
+						t.Fatalf("Failed to append clip: %v", err)
+					}
+				}
+				if err := timeline.Tracks().AppendChild(track); err != nil {
+					t.Fatalf("Failed to append track: %v", err)
+				}
+			}
+
+			svg := encodeString(t, timeline, nil)
+			for _, bad := range []string{"NaN", "Inf", `width="-`, `height="-`} {
+				if strings.Contains(svg, bad) {
+					t.Errorf("SVG contains %q", bad)
+				}
+			}
+		})
+	}
+}
+
+func TestEncodeInvalidDuration(t *testing.T) {
+	sr := opentime.NewTimeRange(opentime.NewRationalTime(0, 24), opentime.NewRationalTime(math.Inf(1), 24))
+	timeline := gotio.NewTimeline("Infinite", nil, nil)
+	track := gotio.NewTrack("V1", nil, gotio.TrackKindVideo, nil, nil)
+	if err := track.AppendChild(gotio.NewClip("Forever", nil, &sr, nil, nil, nil, "", nil)); err != nil {
+		t.Fatalf("Failed to append clip: %v", err)
+	}
+	if err := timeline.Tracks().AppendChild(track); err != nil {
+		t.Fatalf("Failed to append track: %v", err)
+	}
+
+	err := NewEncoder(&bytes.Buffer{}).Encode(timeline)
+	if err == nil || !strings.Contains(err.Error(), "invalid duration") {
+		t.Errorf("Expected invalid duration error, got %v", err)
+	}
+}
+
+func TestRectSize(t *testing.T) {
+	var buf bytes.Buffer
+	builder := NewSVGBuilder(&buf)
+	if err := builder.WriteRect(10, 10, -5, math.NaN(), "#fff", "", "", "", ""); err != nil {
+		t.Fatalf("WriteRect failed: %v", err)
+	}
+	if !strings.Contains(buf.String(), `width="0.00" height="0.00"`) {
+		t.Errorf("Invalid sizes not written as zero: %s", buf.String())
+	}
+}
+
 func TestFormatTime(t *testing.T) {
 	tests := []struct {
 		seconds  float64
@@ -403,6 +499,16 @@ func TestFormatTime(t *testing.T) {
 		{3600.0, "1:00:00"},
 		{3661.0, "1:01:01"},
 		{7265.0, "2:01:05"},
+		{0.0, "0.0s"},
+		{0.04, "0.04s"},
+		{1.0 / 24, "0.042s"},
+		{0.25, "0.25s"},
+		{0.099, "0.099s"},
+		{0.01, "0.01s"},
+		{0.001, "0.001s"},
+		{0.0004, "0.0s"},
+		{14400.0, "4:00:00"},
+		{90000.0, "25:00:00"},
 	}
 
 	for _, tt := range tests {
@@ -446,10 +552,15 @@ func TestCalculateTimeInterval(t *testing.T) {
 		{60.0, 2.0, 10.0},
 		{300.0, 10.0, 60.0},
 		{3600.0, 120.0, 600.0},
+		{1.0 / 24, 0.01, 0.01},          // a single frame
+		{0.5, 0.05, 0.05},               // half a second
+		{2.0, 0.2, 0.2},                 // two seconds
+		{4 * 3600.0, 1800.0, 1800.0},    // four hours
+		{48 * 3600.0, 21600.0, 21600.0}, // two days
 	}
 
 	for _, tt := range tests {
-		result := calculateTimeInterval(tt.duration)
+		result := calculateTimeInterval(tt.duration, DefaultRulerTargetMarks)
 		if result < tt.wantMin || result > tt.wantMax {
 			t.Errorf("calculateTimeInterval(%.1f) = %.1f, want between %.1f and %.1f",
 				tt.duration, result, tt.wantMin, tt.wantMax)
@@ -505,3 +616,1907 @@ func TestCustomSize(t *testing.T) {
 		t.Error("SVG missing custom height")
 	}
 }
+
+// newTestClip creates a clip with the given duration in frames.
+func newTestClip(name string, frames, rate float64) *gotio.Clip {
+	sr := opentime.NewTimeRange(
+		opentime.NewRationalTime(0, rate),
+		opentime.NewRationalTime(frames, rate),
+	)
+	return gotio.NewClip(name, nil, &sr, nil, nil, nil, "", nil)
+}
+
+// buildSimpleTimeline creates a timeline with one video track holding a
+// single 10 second clip.
+func buildSimpleTimeline(t *testing.T) *gotio.Timeline {
+	t.Helper()
+
+	timeline := gotio.NewTimeline("Test Timeline", nil, nil)
+	track := gotio.NewTrack("Video Track", nil, gotio.TrackKindVideo, nil, nil)
+
+	if err := track.AppendChild(newTestClip("Test Clip", 240, 24)); err != nil {
+		t.Fatalf("Failed to append clip: %v", err)
+	}
+
+	if err := timeline.Tracks().AppendChild(track); err != nil {
+		t.Fatalf("Failed to append track: %v", err)
+	}
+
+	return timeline
+}
+
+// encodeString encodes a timeline with the given encoder setup and returns
+// the SVG output.
+func encodeString(t *testing.T, timeline *gotio.Timeline, setup func(*Encoder)) string {
+	t.Helper()
+
+	var buf bytes.Buffer
+	enc := NewEncoder(&buf)
+	if setup != nil {
+		setup(enc)
+	}
+	if err := enc.Encode(timeline); err != nil {
+		t.Fatalf("Failed to encode timeline: %v", err)
+	}
+	return buf.String()
+}
+
+func TestAutoHeight(t *testing.T) {
+	timeline := gotio.NewTimeline("Many Tracks", nil, nil)
+	for i := 0; i < 12; i++ {
+		track := gotio.NewTrack(fmt.Sprintf("V%d", i+1), nil, gotio.TrackKindVideo, nil, nil)
+		if err := track.AppendChild(newTestClip("Clip", 48, 24)); err != nil {
+			t.Fatalf("Failed to append clip: %v", err)
+		}
+		if err := timeline.Tracks().AppendChild(track); err != nil {
+			t.Fatalf("Failed to append track: %v", err)
+		}
+	}
+
+	svg := encodeString(t, timeline, func(enc *Encoder) {
+		enc.SetAutoHeight(true)
+	})
+
+	wantHeight := MarginTop + RulerHeight + 12*TrackHeight + MarginBottom
+	if !strings.Contains(svg, fmt.Sprintf(`height="%d"`, wantHeight)) {
+		t.Errorf("SVG missing computed height %d", wantHeight)
+	}
+
+	if !strings.Contains(svg, fmt.Sprintf(`viewBox="0 0 %d %d"`, DefaultWidth, wantHeight)) {
+		t.Error("SVG viewBox does not reflect computed height")
+	}
+
+	// The last track must be laid out at full track height.
+	lastY := MarginTop + RulerHeight + 11*TrackHeight
+	if !strings.Contains(svg, fmt.Sprintf(`y="%d.00" width="%d.00" height="%d.00"`, lastY, DefaultWidth-MarginLeft-MarginRight, TrackHeight)) {
+		t.Error("Last track not drawn at full track height")
+	}
+}
+
+func TestPixelsPerSecond(t *testing.T) {
+	// 10 second timeline at 50 pixels per second
+	svg := encodeString(t, buildSimpleTimeline(t), func(enc *Encoder) {
+		enc.SetPixelsPerSecond(50)
+	})
+
+	wantWidth := MarginLeft + 500 + MarginRight
+	if !strings.Contains(svg, fmt.Sprintf(`width="%d"`, wantWidth)) {
+		t.Errorf("SVG missing computed width %d", wantWidth)
+	}
+
+	if !strings.Contains(svg, `width="500.00"`) {
+		t.Error("Clip not drawn at fixed scale")
+	}
+}
+
+func TestZoom(t *testing.T) {
+	normal := encodeString(t, buildSimpleTimeline(t), nil)
+	svg := encodeString(t, buildSimpleTimeline(t), func(enc *Encoder) {
+		enc.SetZoom(2)
+	})
+
+	contentWidth := 2 * (DefaultWidth - MarginLeft - MarginRight)
+	wantWidth := MarginLeft + contentWidth + MarginRight
+	if !strings.Contains(svg, fmt.Sprintf(`width="%d"`, wantWidth)) {
+		t.Errorf("SVG missing zoomed width %d", wantWidth)
+	}
+	if !strings.Contains(svg, fmt.Sprintf(`width="%d.00"`, contentWidth)) {
+		t.Error("Clip not drawn at zoomed scale")
+	}
+
+	// Twice the width keeps the ruler as dense, so it has about twice the labels
+	before := strings.Count(normal, `class="ruler-text"`)
+	after := strings.Count(svg, `class="ruler-text"`)
+	if after < 2*before-2 {
+		t.Errorf("Zoomed ruler has %d labels, want about %d", after, 2*before)
+	}
+
+	// Zoom combines with a fixed scale
+	svg = encodeString(t, buildSimpleTimeline(t), func(enc *Encoder) {
+		enc.SetPixelsPerSecond(50)
+		enc.SetZoom(2)
+	})
+	if !strings.Contains(svg, `width="1000.00"`) {
+		t.Error("Zoom should multiply a fixed scale")
+	}
+
+	svg = encodeString(t, buildSimpleTimeline(t), func(enc *Encoder) {
+		enc.SetZoom(-1)
+	})
+	if svg != normal {
+		t.Error("A negative zoom should draw at the normal scale")
+	}
+}
+
+func TestTooltips(t *testing.T) {
+	timeline := gotio.NewTimeline("Tooltips", nil, nil)
+	track := gotio.NewTrack("Video Track", nil, gotio.TrackKindVideo, nil, nil)
+
+	transition := gotio.NewTransition(
+		"Dissolve",
+		gotio.TransitionTypeSMPTEDissolve,
+		opentime.NewRationalTime(12, 24),
+		opentime.NewRationalTime(12, 24),
+		nil,
+	)
+
+	children := []gotio.Composable{
+		newTestClip("Shot <A>", 48, 24),
+		transition,
+		newTestClip("Shot B", 48, 24),
+		gotio.NewGapWithDuration(opentime.NewRationalTime(24, 24)),
+	}
+	for _, child := range children {
+		if err := track.AppendChild(child); err != nil {
+			t.Fatalf("Failed to append child: %v", err)
+		}
+	}
+	if err := timeline.Tracks().AppendChild(track); err != nil {
+		t.Fatalf("Failed to append track: %v", err)
+	}
+
+	svg := encodeString(t, timeline, nil)
+
+	if !strings.Contains(svg, "<title>Shot &lt;A&gt;\nDuration: 2.0s\nSource: 0.0s - 2.0s</title>") {
+		t.Error("SVG missing escaped clip tooltip")
+	}
+
+	if !strings.Contains(svg, "<title>Gap\nDuration: 1.0s</title>") {
+		t.Error("SVG missing gap tooltip")
+	}
+
+	if !strings.Contains(svg, "<title>Dissolve\nType: "+gotio.TransitionTypeSMPTEDissolve+"\nDuration: 1.0s\nIn: 0.5s\nOut: 0.5s</title>") {
+		t.Error("SVG missing transition tooltip")
+	}
+}
+
+func TestDeterministicOutput(t *testing.T) {
+	timeline := gotio.NewTimeline("Deterministic", nil, nil)
+	track := gotio.NewTrack("V1", nil, gotio.TrackKindVideo, nil, nil)
+
+	children := []gotio.Composable{
+		newTestClip("A", 24, 24),
+		gotio.NewGapWithDuration(opentime.NewRationalTime(24, 24)),
+		newTestClip("B", 24, 24),
+		gotio.NewTransition("Dissolve", gotio.TransitionTypeSMPTEDissolve,
+			opentime.NewRationalTime(6, 24), opentime.NewRationalTime(6, 24), nil),
+		newTestClip("C", 24, 24),
+	}
+	for _, child := range children {
+		if err := track.AppendChild(child); err != nil {
+			t.Fatalf("Failed to append child: %v", err)
+		}
+	}
+	if err := timeline.Tracks().AppendChild(track); err != nil {
+		t.Fatalf("Failed to append track: %v", err)
+	}
+
+	first := encodeString(t, timeline, nil)
+	second := encodeString(t, timeline, nil)
+
+	if first != second {
+		t.Error("Encoding the same timeline twice produced different output")
+	}
+
+	if !strings.Contains(first, `id="gap-V1-1"`) {
+		t.Error("SVG missing index-based gap ID")
+	}
+
+	if !strings.Contains(first, `id="transition-V1-3"`) {
+		t.Error("SVG missing index-based transition ID")
+	}
+}
+
+func TestDuplicateIDs(t *testing.T) {
+	timeline := gotio.NewTimeline("Duplicates", nil, nil)
+	for i := 0; i < 2; i++ {
+		track := gotio.NewTrack("V1", nil, gotio.TrackKindVideo, nil, nil)
+		for j := 0; j < 2; j++ {
+			if err := track.AppendChild(newTestClip("Clip", 24, 24)); err != nil {
+				t.Fatalf("Failed to append clip: %v", err)
+			}
+		}
+		if err := timeline.Tracks().AppendChild(track); err != nil {
+			t.Fatalf("Failed to append track: %v", err)
+		}
+	}
+
+	var buf bytes.Buffer
+	enc := NewEncoder(&buf)
+	for pass := 0; pass < 2; pass++ {
+		buf.Reset()
+		if err := enc.Encode(timeline); err != nil {
+			t.Fatalf("Failed to encode timeline: %v", err)
+		}
+		svg := buf.String()
+
+		for _, id := range []string{"track-V1", "track-V1-2", "clip-Clip", "clip-Clip-2", "clip-Clip-3", "clip-Clip-4"} {
+			if strings.Count(svg, fmt.Sprintf(`id="%s"`, id)) != 1 {
+				t.Errorf("pass %d: expected exactly one element with id %q", pass, id)
+			}
+		}
+
+		if strings.Contains(svg, `id="clip-Clip-5"`) {
+			t.Errorf("pass %d: ID registry was not reset between encodes", pass)
+		}
+	}
+}
+
+func TestEncodeNestedStack(t *testing.T) {
+	timeline := gotio.NewTimeline("Nested", nil, nil)
+	track := gotio.NewTrack("V1", nil, gotio.TrackKindVideo, nil, nil)
+
+	stack := gotio.NewStack("Nested Stack", nil, nil, nil, nil, nil)
+	for _, name := range []string{"Inner A", "Inner B"} {
+		inner := gotio.NewTrack(name, nil, gotio.TrackKindVideo, nil, nil)
+		if err := inner.AppendChild(newTestClip(name, 48, 24)); err != nil {
+			t.Fatalf("Failed to append clip: %v", err)
+		}
+		if err := stack.AppendChild(inner); err != nil {
+			t.Fatalf("Failed to append inner track: %v", err)
+		}
+	}
+
+	if err := track.AppendChild(newTestClip("Outer", 48, 24)); err != nil {
+		t.Fatalf("Failed to append clip: %v", err)
+	}
+	if err := track.AppendChild(stack); err != nil {
+		t.Fatalf("Failed to append stack: %v", err)
+	}
+	if err := timeline.Tracks().AppendChild(track); err != nil {
+		t.Fatalf("Failed to append track: %v", err)
+	}
+
+	svg := encodeString(t, timeline, nil)
+
+	if !strings.Contains(svg, `class="stack"`) {
+		t.Error("SVG missing nested stack group")
+	}
+
+	if !strings.Contains(svg, `id="clip-Inner_A"`) || !strings.Contains(svg, `id="clip-Inner_B"`) {
+		t.Error("SVG missing clips from nested tracks")
+	}
+
+	// 4 second timeline, the stack starts halfway across the content area.
+	stackX := float64(MarginLeft) + float64(DefaultWidth-MarginLeft-MarginRight)/2
+	if !strings.Contains(svg, fmt.Sprintf(`<rect x="%.2f"`, stackX)) {
+		t.Errorf("Nested content not positioned at x=%.2f", stackX)
+	}
+}
+
+func TestPlayhead(t *testing.T) {
+	svg := encodeString(t, buildSimpleTimeline(t), func(enc *Encoder) {
+		enc.SetPlayhead(opentime.NewRationalTime(120, 24)) // 5 seconds
+	})
+
+	if !strings.Contains(svg, `class="playhead"`) {
+		t.Fatal("SVG missing playhead")
+	}
+
+	x := float64(MarginLeft) + float64(DefaultWidth-MarginLeft-MarginRight)/2
+	if !strings.Contains(svg, fmt.Sprintf(`x1="%.2f" y1="%d.00" x2="%.2f"`, x, MarginTop, x)) {
+		t.Errorf("Playhead line not drawn at x=%.2f", x)
+	}
+
+	if !strings.Contains(svg, `stroke="`+PlayheadColor+`"`) {
+		t.Error("Playhead not drawn in theme color")
+	}
+
+	outside := encodeString(t, buildSimpleTimeline(t), func(enc *Encoder) {
+		enc.SetPlayhead(opentime.NewRationalTime(480, 24)) // 20 seconds
+	})
+
+	if strings.Contains(outside, `class="playhead"`) {
+		t.Error("Playhead outside the timeline should not be drawn")
+	}
+}
+
+func TestHighlights(t *testing.T) {
+	svg := encodeString(t, buildSimpleTimeline(t), func(enc *Encoder) {
+		enc.AddHighlight(opentime.NewTimeRange(
+			opentime.NewRationalTime(24, 24),
+			opentime.NewRationalTime(48, 24),
+		), "Review")
+		// Extends past the end of the 10 second timeline
+		enc.AddHighlight(opentime.NewTimeRange(
+			opentime.NewRationalTime(216, 24),
+			opentime.NewRationalTime(240, 24),
+		), "")
+	})
+
+	if strings.Count(svg, `class="highlight"`) != 2 {
+		t.Errorf("Expected 2 highlights, found %d", strings.Count(svg, `class="highlight"`))
+	}
+
+	if !strings.Contains(svg, ">Review</text>") {
+		t.Error("SVG missing highlight label")
+	}
+
+	// The second highlight starts at 9s and is clamped to the content edge.
+	contentWidth := float64(DefaultWidth - MarginLeft - MarginRight)
+	x := float64(MarginLeft) + contentWidth*0.9
+	if !strings.Contains(svg, fmt.Sprintf(`x="%.2f" y="%d.00" width="%.2f"`, x, MarginTop+RulerHeight, contentWidth*0.1)) {
+		t.Error("Highlight extending past the timeline was not clamped")
+	}
+
+	if strings.Index(svg, `class="highlights"`) < strings.LastIndex(svg, `class="track"`) {
+		t.Error("Highlights must be drawn after tracks")
+	}
+}
+
+func TestTransitionStraddlesCut(t *testing.T) {
+	timeline := gotio.NewTimeline("Transition Placement", nil, nil)
+	track := gotio.NewTrack("V1", nil, gotio.TrackKindVideo, nil, nil)
+
+	children := []gotio.Composable{
+		newTestClip("A", 120, 24),
+		gotio.NewTransition("Dissolve", gotio.TransitionTypeSMPTEDissolve,
+			opentime.NewRationalTime(24, 24), opentime.NewRationalTime(24, 24), nil),
+		newTestClip("B", 120, 24),
+	}
+	for _, child := range children {
+		if err := track.AppendChild(child); err != nil {
+			t.Fatalf("Failed to append child: %v", err)
+		}
+	}
+	if err := timeline.Tracks().AppendChild(track); err != nil {
+		t.Fatalf("Failed to append track: %v", err)
+	}
+
+	svg := encodeString(t, timeline, func(enc *Encoder) {
+		enc.SetPixelsPerSecond(100)
+	})
+
+	// Clip B starts at the cut (5s), and the transition spans 4s-6s.
+	if !strings.Contains(svg, fmt.Sprintf(`<rect x="%d.00"`, MarginLeft+500)) {
+		t.Error("Clip after transition is not positioned at the cut")
+	}
+
+	if !strings.Contains(svg, fmt.Sprintf(`d="M %d.00`, MarginLeft+400)) {
+		t.Error("Transition does not start at its in offset before the cut")
+	}
+}
+
+func TestItemPlacementMatchesTrackRanges(t *testing.T) {
+	timeline := gotio.NewTimeline("Placement", nil, nil)
+	track := gotio.NewTrack("V1", nil, gotio.TrackKindVideo, nil, nil)
+
+	children := []gotio.Composable{
+		newTestClip("A", 24, 24),
+		gotio.NewGapWithDuration(opentime.NewRationalTime(12, 24)),
+		gotio.NewTransition("Dissolve", gotio.TransitionTypeSMPTEDissolve,
+			opentime.NewRationalTime(6, 24), opentime.NewRationalTime(6, 24), nil),
+		newTestClip("B", 48000, 48000),
+	}
+	for _, child := range children {
+		if err := track.AppendChild(child); err != nil {
+			t.Fatalf("Failed to append child: %v", err)
+		}
+	}
+	if err := timeline.Tracks().AppendChild(track); err != nil {
+		t.Fatalf("Failed to append track: %v", err)
+	}
+
+	svg := encodeString(t, timeline, func(enc *Encoder) {
+		enc.SetPixelsPerSecond(100)
+	})
+
+	for i := range children {
+		rng, err := track.RangeOfChildAtIndex(i)
+		if err != nil {
+			t.Fatalf("RangeOfChildAtIndex(%d) failed: %v", i, err)
+		}
+		x := float64(MarginLeft) + rng.StartTime().ToSeconds()*100
+		if !strings.Contains(svg, fmt.Sprintf(`x="%.2f"`, x)) && !strings.Contains(svg, fmt.Sprintf(`d="M %.2f`, x)) {
+			t.Errorf("Child %d not positioned at x=%.2f", i, x)
+		}
+	}
+}
+
+func TestTimelineTitle(t *testing.T) {
+	svg := encodeString(t, buildSimpleTimeline(t), nil)
+	if !strings.Contains(svg, `class="timeline-title" dominant-baseline="middle">Test Timeline</text>`) {
+		t.Error("SVG missing timeline title")
+	}
+
+	svg = encodeString(t, buildSimpleTimeline(t), func(enc *Encoder) {
+		enc.SetShowTitle(false)
+	})
+	if strings.Contains(svg, `class="timeline-title"`) {
+		t.Error("Title drawn although disabled")
+	}
+
+	// A top margin too small for the title is expanded.
+	var buf bytes.Buffer
+	enc := NewEncoder(&buf, WithMargins(10, MarginRight, MarginBottom, MarginLeft))
+	if err := enc.Encode(buildSimpleTimeline(t)); err != nil {
+		t.Fatalf("Failed to encode timeline: %v", err)
+	}
+	if !strings.Contains(buf.String(), fmt.Sprintf(`y="%d.00" width="%d.00" height="%d.00" fill="%s"`,
+		TitleHeight, DefaultWidth-MarginLeft-MarginRight, RulerHeight, TrackLabelBg)) {
+		t.Error("Ruler not moved down to make room for the title")
+	}
+
+	// An unnamed timeline has no heading.
+	unnamed := gotio.NewTimeline("", nil, nil)
+	track := gotio.NewTrack("V1", nil, gotio.TrackKindVideo, nil, nil)
+	if err := track.AppendChild(newTestClip("Clip", 24, 24)); err != nil {
+		t.Fatalf("Failed to append clip: %v", err)
+	}
+	if err := unnamed.Tracks().AppendChild(track); err != nil {
+		t.Fatalf("Failed to append track: %v", err)
+	}
+	svg = encodeString(t, unnamed, nil)
+	if strings.Contains(svg, `class="timeline-title"`) {
+		t.Error("Title drawn for unnamed timeline")
+	}
+}
+
+func TestDocumentTitleDesc(t *testing.T) {
+	timeline := gotio.NewTimeline("Cut <v2> & notes", nil, nil)
+	track := gotio.NewTrack("V1", nil, gotio.TrackKindVideo, nil, nil)
+	for _, name := range []string{"A", "B"} {
+		if err := track.AppendChild(newTestClip(name, 72, 24)); err != nil {
+			t.Fatalf("Failed to append clip: %v", err)
+		}
+	}
+	if err := timeline.Tracks().AppendChild(track); err != nil {
+		t.Fatalf("Failed to append track: %v", err)
+	}
+
+	svg := encodeString(t, timeline, nil)
+
+	want := "<title>Cut &lt;v2&gt; &amp; notes</title>\n  <desc>1 track, 2 clips, duration 6.0s</desc>\n"
+	header := svg[strings.Index(svg, "<svg "):]
+	header = header[strings.Index(header, "\n")+1:]
+	if !strings.HasPrefix(header, "  "+want) {
+		t.Errorf("SVG root does not start with title and desc, got:\n%s", header[:80])
+	}
+}
+
+func TestClipLabelContrast(t *testing.T) {
+	theme := DefaultTheme()
+	theme.VideoTrack = "#202020"
+	svg := encodeString(t, buildSimpleTimeline(t), func(enc *Encoder) {
+		enc.SetTheme(theme)
+	})
+	if !strings.Contains(svg, `class="clip-label" style="fill: #FFFFFF"`) {
+		t.Error("Dark clip should have a white label")
+	}
+
+	theme.VideoTrack = "#F0F0A0"
+	svg = encodeString(t, buildSimpleTimeline(t), func(enc *Encoder) {
+		enc.SetTheme(theme)
+	})
+	if !strings.Contains(svg, `class="clip-label" style="fill: #000000"`) {
+		t.Error("Light clip should have a black label")
+	}
+}
+
+func TestClipLabelTruncation(t *testing.T) {
+	timeline := gotio.NewTimeline("Truncation", nil, nil)
+	track := gotio.NewTrack("V1", nil, gotio.TrackKindVideo, nil, nil)
+	children := []gotio.Composable{
+		newTestClip("An extremely long clip name that cannot fit", 24, 24),
+		newTestClip("Tiny", 1, 24),
+		newTestClip("Filler", 216, 24),
+	}
+	for _, child := range children {
+		if err := track.AppendChild(child); err != nil {
+			t.Fatalf("Failed to append clip: %v", err)
+		}
+	}
+	if err := timeline.Tracks().AppendChild(track); err != nil {
+		t.Fatalf("Failed to append track: %v", err)
+	}
+
+	// 1 second is 106 pixels wide
+	svg := encodeString(t, timeline, nil)
+
+	if strings.Contains(svg, ">An extremely long clip name that cannot fit</text>") {
+		t.Error("Long clip name was not truncated")
+	}
+
+	if !strings.Contains(svg, ">An extremely lo…</text>") {
+		t.Error("Truncated clip name missing ellipsis")
+	}
+
+	if strings.Contains(svg, ">Tiny</text>") || strings.Contains(svg, ">…</text>") {
+		t.Error("Label drawn in a clip too narrow for it")
+	}
+}
+
+func TestGapDurationLabel(t *testing.T) {
+	timeline := gotio.NewTimeline("Gap Labels", nil, nil)
+	track := gotio.NewTrack("V1", nil, gotio.TrackKindVideo, nil, nil)
+	children := []gotio.Composable{
+		newTestClip("A", 48, 24),
+		gotio.NewGapWithDuration(opentime.NewRationalTime(48, 24)),
+		newTestClip("B", 48, 24),
+		gotio.NewGapWithDuration(opentime.NewRationalTime(1, 24)),
+		newTestClip("C", 48, 24),
+	}
+	for _, child := range children {
+		if err := track.AppendChild(child); err != nil {
+			t.Fatalf("Failed to append child: %v", err)
+		}
+	}
+	if err := timeline.Tracks().AppendChild(track); err != nil {
+		t.Fatalf("Failed to append track: %v", err)
+	}
+
+	svg := encodeString(t, timeline, nil)
+	if !strings.Contains(svg, `class="clip-label" style="fill: `+GapTextColor+`" dominant-baseline="middle">2.0s</text>`) {
+		t.Error("SVG missing gap duration label")
+	}
+	if strings.Count(svg, `style="fill: `+GapTextColor+`"`) != 1 {
+		t.Error("Narrow gap should not be labeled")
+	}
+
+	svg = encodeString(t, timeline, func(enc *Encoder) {
+		enc.SetTimeFormat(TimeFormatFrames)
+	})
+	if !strings.Contains(svg, `style="fill: `+GapTextColor+`" dominant-baseline="middle">48</text>`) {
+		t.Error("Gap label does not respect the time format")
+	}
+}
+
+func TestGrid(t *testing.T) {
+	svg := encodeString(t, buildSimpleTimeline(t), nil)
+	if strings.Contains(svg, `class="grid"`) {
+		t.Error("Grid should be off by default")
+	}
+
+	svg = encodeString(t, buildSimpleTimeline(t), func(enc *Encoder) {
+		enc.SetShowGrid(true)
+	})
+
+	ticks := strings.Count(svg, `class="tick"`)
+	if lines := strings.Count(svg, `class="grid-line"`); lines != ticks {
+		t.Errorf("Expected %d grid lines, found %d", ticks, lines)
+	}
+
+	if strings.Index(svg, `class="grid"`) > strings.Index(svg, `class="clip"`) {
+		t.Error("Grid must be drawn behind clips")
+	}
+
+	top := MarginTop + RulerHeight
+	if !strings.Contains(svg, fmt.Sprintf(`y1="%d.00" x2="%d.00" y2="%d.00"`, top, MarginLeft, top+TrackHeight)) {
+		t.Error("Grid line does not span the track area")
+	}
+}
+
+func TestZebraStripes(t *testing.T) {
+	buildTimeline := func() *gotio.Timeline {
+		timeline := gotio.NewTimeline("Zebra", nil, nil)
+		kinds := []string{gotio.TrackKindVideo, gotio.TrackKindVideo, gotio.TrackKindAudio, gotio.TrackKindAudio}
+		for i, kind := range kinds {
+			track := gotio.NewTrack(fmt.Sprintf("T%d", i+1), nil, kind, nil, nil)
+			if err := track.AppendChild(newTestClip("Clip", 48, 24)); err != nil {
+				t.Fatalf("Failed to append clip: %v", err)
+			}
+			if err := timeline.Tracks().AppendChild(track); err != nil {
+				t.Fatalf("Failed to append track: %v", err)
+			}
+		}
+		return timeline
+	}
+
+	svg := encodeString(t, buildTimeline(), nil)
+	if strings.Contains(svg, zebraAlpha+`"`) {
+		t.Error("Zebra stripes should be off by default")
+	}
+
+	svg = encodeString(t, buildTimeline(), func(enc *Encoder) {
+		enc.SetZebraStripes(true)
+	})
+
+	for _, fill := range []string{
+		VideoTrackColor + "33", VideoTrackColor + zebraAlpha,
+		AudioTrackColor + "33", AudioTrackColor + zebraAlpha,
+	} {
+		if n := strings.Count(svg, `fill="`+fill+`"`); n != 1 {
+			t.Errorf("Expected 1 track background with fill %s, found %d", fill, n)
+		}
+	}
+}
+
+func TestTrackSeparators(t *testing.T) {
+	timeline := gotio.NewTimeline("Separators", nil, nil)
+	for _, kind := range []string{gotio.TrackKindVideo, gotio.TrackKindVideo, gotio.TrackKindAudio} {
+		track := gotio.NewTrack("", nil, kind, nil, nil)
+		if err := track.AppendChild(newTestClip("Clip", 48, 24)); err != nil {
+			t.Fatalf("Failed to append clip: %v", err)
+		}
+		if err := timeline.Tracks().AppendChild(track); err != nil {
+			t.Fatalf("Failed to append track: %v", err)
+		}
+	}
+
+	svg := encodeString(t, timeline, nil)
+	if strings.Contains(svg, "track-separator") {
+		t.Error("Track separators should be off by default")
+	}
+
+	svg = encodeString(t, timeline, func(enc *Encoder) {
+		enc.SetShowTrackSeparators(true)
+	})
+	if n := strings.Count(svg, `class="track-separator"`); n != 4 {
+		t.Errorf("Expected 4 track separators for 3 tracks, found %d", n)
+	}
+	top := MarginTop + RulerHeight
+	for i := 0; i <= 3; i++ {
+		y := top + i*TrackHeight
+		line := fmt.Sprintf(`x1="%d.00" y1="%d.00" x2="%d.00" y2="%d.00" stroke="%s" stroke-width="1.00" class="track-separator"`,
+			MarginLeft, y, DefaultWidth-MarginRight, y, GridColor)
+		if !strings.Contains(svg, line) {
+			t.Errorf("Missing track separator at y=%d", y)
+		}
+	}
+
+	// Separators are drawn over the clips but under the kind divider
+	svg = encodeString(t, timeline, func(enc *Encoder) {
+		enc.SetShowTrackSeparators(true)
+		enc.SetGroupByKind(true)
+	})
+	if strings.LastIndex(svg, `class="track-separator"`) > strings.Index(svg, `class="kind-divider"`) {
+		t.Error("Track separators should be drawn before the kind divider")
+	}
+	if strings.Index(svg, `class="track-separator"`) < strings.LastIndex(svg, `class="clip`) {
+		t.Error("Track separators should be drawn after the clips")
+	}
+}
+
+func TestMissingMedia(t *testing.T) {
+	sr := opentime.NewTimeRange(opentime.NewRationalTime(0, 24), opentime.NewRationalTime(48, 24))
+	clips := []*gotio.Clip{
+		gotio.NewClip("Linked", gotio.NewExternalReference("", "file:///media/linked.mov", nil, nil), &sr, nil, nil, nil, "", nil),
+		gotio.NewClip("Missing", gotio.NewMissingReference("", nil, nil), &sr, nil, nil, nil, "", nil),
+		gotio.NewClip("Unset", nil, &sr, nil, nil, nil, "", nil),
+	}
+
+	timeline := gotio.NewTimeline("Missing Media", nil, nil)
+	track := gotio.NewTrack("V1", nil, gotio.TrackKindVideo, nil, nil)
+	for _, clip := range clips {
+		if err := track.AppendChild(clip); err != nil {
+			t.Fatalf("Failed to append clip: %v", err)
+		}
+	}
+	if err := timeline.Tracks().AppendChild(track); err != nil {
+		t.Fatalf("Failed to append track: %v", err)
+	}
+
+	svg := encodeString(t, timeline, nil)
+
+	if n := strings.Count(svg, `class="clip"`); n != 3 {
+		t.Errorf("Expected 3 clips, found %d", n)
+	}
+	if n := strings.Count(svg, `class="missing-media"`); n != 2 {
+		t.Errorf("Expected 2 missing media outlines, found %d", n)
+	}
+	if n := strings.Count(svg, "<title>Media missing</title>"); n != 2 {
+		t.Errorf("Expected 2 missing media tooltips, found %d", n)
+	}
+	linked := strings.Index(svg, `id="clip-Linked"`)
+	if next := strings.Index(svg[linked:], "<rect "); strings.Contains(svg[linked:linked+next], "missing-media") {
+		t.Error("Clip with media should not be outlined as missing")
+	}
+	if !strings.Contains(svg, "stroke: "+MissingMediaColor+";") {
+		t.Error("CSS missing missing media border color")
+	}
+}
+
+func TestMediaLinks(t *testing.T) {
+	sr := opentime.NewTimeRange(opentime.NewRationalTime(0, 24), opentime.NewRationalTime(48, 24))
+	clips := []*gotio.Clip{
+		gotio.NewClip("Linked", gotio.NewExternalReference("", "https://example.com/a.mov?x=1&y=\"2\"", nil, nil), &sr, nil, nil, nil, "", nil),
+		gotio.NewClip("Unlinked", gotio.NewExternalReference("", "", nil, nil), &sr, nil, nil, nil, "", nil),
+		newTestClip("Unset", 48, 24),
+	}
+
+	timeline := gotio.NewTimeline("Links", nil, nil)
+	track := gotio.NewTrack("V1", nil, gotio.TrackKindVideo, nil, nil)
+	for _, clip := range clips {
+		if err := track.AppendChild(clip); err != nil {
+			t.Fatalf("Failed to append clip: %v", err)
+		}
+	}
+	if err := timeline.Tracks().AppendChild(track); err != nil {
+		t.Fatalf("Failed to append track: %v", err)
+	}
+
+	svg := encodeString(t, timeline, nil)
+
+	if n := strings.Count(svg, "<a "); n != 1 {
+		t.Fatalf("Expected 1 link, found %d", n)
+	}
+	if !strings.Contains(svg, `<a href="https://example.com/a.mov?x=1&amp;y=&quot;2&quot;" aria-label="Open media for Linked">`) {
+		t.Error("Link URL not escaped")
+	}
+
+	start := strings.Index(svg, "<a ")
+	end := strings.Index(svg, "</a>")
+	if !strings.Contains(svg[start:end], `id="clip-Linked"`) {
+		t.Error("Link does not wrap the linked clip")
+	}
+}
+
+func TestDisabledItems(t *testing.T) {
+	build := func(disable bool) *gotio.Timeline {
+		timeline := gotio.NewTimeline("Disabled", nil, nil)
+		track := gotio.NewTrack("V1", nil, gotio.TrackKindVideo, nil, nil)
+		muted := newTestClip("Muted", 48, 24)
+		muted.SetEnabled(!disable)
+		for _, clip := range []*gotio.Clip{muted, newTestClip("Active", 48, 24)} {
+			if err := track.AppendChild(clip); err != nil {
+				t.Fatalf("Failed to append clip: %v", err)
+			}
+		}
+		audio := gotio.NewTrack("A1", nil, gotio.TrackKindAudio, nil, nil)
+		audio.SetEnabled(!disable)
+		for _, child := range []gotio.Composable{track, audio} {
+			if err := timeline.Tracks().AppendChild(child); err != nil {
+				t.Fatalf("Failed to append track: %v", err)
+			}
+		}
+		return timeline
+	}
+
+	svg := encodeString(t, build(true), nil)
+
+	if !strings.Contains(svg, ".disabled {") {
+		t.Error("CSS missing disabled rule")
+	}
+	if !strings.Contains(svg, `<g class="disabled">`+"\n") || strings.Count(svg, `<g class="disabled">`) != 1 {
+		t.Error("Expected the disabled clip to be wrapped in one disabled group")
+	}
+	if !strings.Contains(svg, `id="track-A1" class="track disabled"`) {
+		t.Error("Disabled track missing disabled class")
+	}
+
+	// Dimming must not move anything
+	enabled := encodeString(t, build(false), nil)
+	activeX := func(svg string) string {
+		i := strings.Index(svg, `id="clip-Active"`)
+		return svg[strings.LastIndex(svg[:i], "<rect "):i]
+	}
+	if activeX(svg) != activeX(enabled) {
+		t.Errorf("Disabled clip shifted its neighbour: %q vs %q", activeX(svg), activeX(enabled))
+	}
+}
+
+func TestSkipDisabledTracks(t *testing.T) {
+	build := func() *gotio.Timeline {
+		timeline := gotio.NewTimeline("", nil, nil)
+		for _, name := range []string{"V1", "V2", "V3"} {
+			track := gotio.NewTrack(name, nil, gotio.TrackKindVideo, nil, nil)
+			track.SetEnabled(name != "V2")
+			if err := track.AppendChild(newTestClip(name+" Clip", 48, 24)); err != nil {
+				t.Fatalf("Failed to append clip: %v", err)
+			}
+			if err := timeline.Tracks().AppendChild(track); err != nil {
+				t.Fatalf("Failed to append track: %v", err)
+			}
+		}
+		return timeline
+	}
+
+	svg := encodeString(t, build(), func(enc *Encoder) {
+		enc.SetAutoHeight(true)
+	})
+	if !strings.Contains(svg, `id="track-V2"`) {
+		t.Error("Disabled track should be drawn by default")
+	}
+
+	svg = encodeString(t, build(), func(enc *Encoder) {
+		enc.SetAutoHeight(true)
+		enc.SetSkipDisabledTracks(true)
+	})
+	if strings.Contains(svg, `id="track-V2"`) {
+		t.Error("Disabled track should be skipped")
+	}
+
+	// V3 takes the lane V2 would have used
+	secondLane := MarginTop + RulerHeight + TrackHeight
+	if !strings.Contains(svg, fmt.Sprintf(`y="%d.00" width="%.2f" height="%d.00" fill="%s33"`, secondLane, float64(DefaultWidth-MarginLeft-MarginRight), TrackHeight, VideoTrackColor)) {
+		t.Error("Remaining track does not fill the skipped lane")
+	}
+	if !strings.Contains(svg, fmt.Sprintf(`height="%d"`, MarginTop+RulerHeight+2*TrackHeight+MarginBottom)) {
+		t.Error("Height should only count drawn tracks")
+	}
+}
+
+func TestTrackKindFilter(t *testing.T) {
+	build := func() *gotio.Timeline {
+		timeline := gotio.NewTimeline("", nil, nil)
+		tracks := []*gotio.Track{
+			gotio.NewTrack("V1", nil, gotio.TrackKindVideo, nil, nil),
+			gotio.NewTrack("A1", nil, gotio.TrackKindAudio, nil, nil),
+			gotio.NewTrack("A2", nil, gotio.TrackKindAudio, nil, nil),
+		}
+		for _, track := range tracks {
+			if err := track.AppendChild(newTestClip(track.Name()+" Clip", 48, 24)); err != nil {
+				t.Fatalf("Failed to append clip: %v", err)
+			}
+			if err := timeline.Tracks().AppendChild(track); err != nil {
+				t.Fatalf("Failed to append track: %v", err)
+			}
+		}
+		return timeline
+	}
+
+	svg := encodeString(t, build(), func(enc *Encoder) {
+		enc.SetAutoHeight(true)
+		enc.SetTrackKindFilter(gotio.TrackKindAudio)
+	})
+
+	if strings.Contains(svg, `id="track-V1"`) {
+		t.Error("Video track should be filtered out")
+	}
+	if !strings.Contains(svg, `id="track-A1"`) || !strings.Contains(svg, `id="track-A2"`) {
+		t.Error("Audio tracks should be rendered")
+	}
+	if !strings.Contains(svg, fmt.Sprintf(`height="%d"`, MarginTop+RulerHeight+2*TrackHeight+MarginBottom)) {
+		t.Error("Height should only count filtered tracks")
+	}
+
+	svg = encodeString(t, build(), func(enc *Encoder) {
+		enc.SetTrackKindFilter(gotio.TrackKindAudio)
+		enc.SetTrackKindFilter()
+	})
+	if n := strings.Count(svg, `class="track"`); n != 3 {
+		t.Errorf("Empty filter should render all 3 tracks, found %d", n)
+	}
+
+	var buf bytes.Buffer
+	enc := NewEncoder(&buf)
+	enc.SetTrackKindFilter("Subtitle")
+	if err := enc.Encode(build()); err == nil {
+		t.Error("Expected error when the filter matches no tracks")
+	}
+}
+
+func TestGroupByKind(t *testing.T) {
+	build := func() *gotio.Timeline {
+		timeline := gotio.NewTimeline("", nil, nil)
+		tracks := []*gotio.Track{
+			gotio.NewTrack("A1", nil, gotio.TrackKindAudio, nil, nil),
+			gotio.NewTrack("V1", nil, gotio.TrackKindVideo, nil, nil),
+			gotio.NewTrack("A2", nil, gotio.TrackKindAudio, nil, nil),
+			gotio.NewTrack("V2", nil, gotio.TrackKindVideo, nil, nil),
+		}
+		for _, track := range tracks {
+			if err := track.AppendChild(newTestClip(track.Name()+" Clip", 48, 24)); err != nil {
+				t.Fatalf("Failed to append clip: %v", err)
+			}
+			if err := timeline.Tracks().AppendChild(track); err != nil {
+				t.Fatalf("Failed to append track: %v", err)
+			}
+		}
+		return timeline
+	}
+
+	svg := encodeString(t, build(), nil)
+	if strings.Contains(svg, `class="kind-divider"`) {
+		t.Error("Divider should only be drawn when grouping by kind")
+	}
+
+	svg = encodeString(t, build(), func(enc *Encoder) {
+		enc.SetAutoHeight(true)
+		enc.SetGroupByKind(true)
+	})
+
+	var order []int
+	for _, name := range []string{"V1", "V2", "A1", "A2"} {
+		order = append(order, strings.Index(svg, `id="track-`+name+`"`))
+	}
+	if !sort.IntsAreSorted(order) {
+		t.Errorf("Expected lanes V1, V2, A1, A2, found offsets %v", order)
+	}
+
+	divider := float64(MarginTop + RulerHeight + 2*TrackHeight)
+	if !strings.Contains(svg, fmt.Sprintf(`y1="%.2f" x2="%.2f" y2="%.2f"`, divider, float64(DefaultWidth-MarginRight), divider)) {
+		t.Error("Divider not drawn between video and audio lanes")
+	}
+}
+
+func TestReverseTrackOrder(t *testing.T) {
+	build := func() *gotio.Timeline {
+		timeline := gotio.NewTimeline("", nil, nil)
+		tracks := []*gotio.Track{
+			gotio.NewTrack("V1", nil, gotio.TrackKindVideo, nil, nil),
+			gotio.NewTrack("A1", nil, gotio.TrackKindAudio, nil, nil),
+			gotio.NewTrack("V2", nil, gotio.TrackKindVideo, nil, nil),
+			gotio.NewTrack("A2", nil, gotio.TrackKindAudio, nil, nil),
+		}
+		for _, track := range tracks {
+			if err := track.AppendChild(newTestClip(track.Name()+" Clip", 48, 24)); err != nil {
+				t.Fatalf("Failed to append clip: %v", err)
+			}
+			if err := timeline.Tracks().AppendChild(track); err != nil {
+				t.Fatalf("Failed to append track: %v", err)
+			}
+		}
+		return timeline
+	}
+
+	tests := []struct {
+		name     string
+		group    bool
+		expected []string
+	}{
+		{"reversed", false, []string{"A2", "V2", "A1", "V1"}},
+		{"reversed and grouped", true, []string{"V2", "V1", "A2", "A1"}},
+	}
+
+	for _, tt := range tests {
+		svg := encodeString(t, build(), func(enc *Encoder) {
+			enc.SetReverseTrackOrder(true)
+			enc.SetGroupByKind(tt.group)
+		})
+
+		var order []int
+		for _, name := range tt.expected {
+			order = append(order, strings.Index(svg, `id="track-`+name+`"`))
+		}
+		if !sort.IntsAreSorted(order) {
+			t.Errorf("%s: expected lanes %v, found offsets %v", tt.name, tt.expected, order)
+		}
+	}
+}
+
+func TestLastLayout(t *testing.T) {
+	timeline := gotio.NewTimeline("", nil, nil)
+	track := gotio.NewTrack("V1", nil, gotio.TrackKindVideo, nil, nil)
+	for _, name := range []string{"Shot", "Shot"} {
+		if err := track.AppendChild(newTestClip(name, 120, 24)); err != nil {
+			t.Fatalf("Failed to append clip: %v", err)
+		}
+	}
+	if err := timeline.Tracks().AppendChild(track); err != nil {
+		t.Fatalf("Failed to append track: %v", err)
+	}
+
+	var buf bytes.Buffer
+	enc := NewEncoder(&buf)
+	if err := enc.Encode(timeline); err != nil {
+		t.Fatalf("Failed to encode timeline: %v", err)
+	}
+	if enc.LastLayout() != nil {
+		t.Error("Layout should not be recorded by default")
+	}
+
+	buf.Reset()
+	enc.SetRecordLayout(true)
+	if err := enc.Encode(timeline); err != nil {
+		t.Fatalf("Failed to encode timeline: %v", err)
+	}
+
+	rects := enc.LastLayout()
+	if len(rects) != 2 {
+		t.Fatalf("Expected 2 clip rects, got %d", len(rects))
+	}
+
+	contentWidth := float64(DefaultWidth - MarginLeft - MarginRight)
+	for id, x := range map[string]float64{"clip-Shot": MarginLeft, "clip-Shot-2": MarginLeft + contentWidth/2} {
+		r, ok := rects[id]
+		if !ok {
+			t.Errorf("Missing rect for %s", id)
+			continue
+		}
+		attrs := fmt.Sprintf(`x="%.2f" y="%.2f" width="%.2f" height="%.2f" fill="%s" stroke="%s" id="%s"`,
+			r.X, r.Y, r.Width, r.Height, VideoTrackColor, ClipBorderColor, id)
+		if !strings.Contains(buf.String(), attrs) {
+			t.Errorf("Rect for %s does not match the SVG: %+v", id, r)
+		}
+		if r.X != x {
+			t.Errorf("Expected %s at x=%.2f, got %.2f", id, x, r.X)
+		}
+	}
+}
+
+func TestFractionalRatePlacement(t *testing.T) {
+	// 100 clips of uneven lengths at 23.976 fps
+	const rate = 24000.0 / 1001.0
+	timeline := gotio.NewTimeline("NTSC", nil, nil)
+	track := gotio.NewTrack("V1", nil, gotio.TrackKindVideo, nil, nil)
+	var starts []float64
+	total := 0.0
+	for i := 0; i < 100; i++ {
+		frames := float64(20 + i%7)
+		starts = append(starts, total)
+		total += frames
+		if err := track.AppendChild(newTestClip(fmt.Sprintf("Shot %03d", i), frames, rate)); err != nil {
+			t.Fatalf("Failed to append clip: %v", err)
+		}
+	}
+	if err := timeline.Tracks().AppendChild(track); err != nil {
+		t.Fatalf("Failed to append track: %v", err)
+	}
+
+	var buf bytes.Buffer
+	enc := NewEncoder(&buf)
+	enc.SetRecordLayout(true)
+	if err := enc.Encode(timeline); err != nil {
+		t.Fatalf("Failed to encode timeline: %v", err)
+	}
+	rects := enc.LastLayout()
+
+	// Each clip starts at its frame's share of the content width, and ends
+	// exactly where the next one starts
+	contentWidth := float64(DefaultWidth - MarginLeft - MarginRight)
+	for i, start := range starts {
+		rect, ok := rects[fmt.Sprintf("clip-Shot_%03d", i)]
+		if !ok {
+			t.Fatalf("Clip %d not recorded", i)
+		}
+		if want := MarginLeft + start/total*contentWidth; math.Abs(rect.X-want) > 1e-9 {
+			t.Errorf("Clip %d starts at %v, want %v", i, rect.X, want)
+		}
+		if i+1 < len(starts) {
+			next := rects[fmt.Sprintf("clip-Shot_%03d", i+1)]
+			if math.Abs(rect.X+rect.Width-next.X) > 1e-9 {
+				t.Errorf("Clip %d ends at %v, but clip %d starts at %v", i, rect.X+rect.Width, i+1, next.X)
+			}
+		}
+	}
+}
+
+func TestIDPrefix(t *testing.T) {
+	setup := func(enc *Encoder) {
+		enc.SetShowGrid(true)
+		enc.SetShowLegend(true)
+		enc.SetPlayhead(opentime.NewRationalTime(24, 24))
+	}
+
+	plain := encodeString(t, buildSimpleTimeline(t), setup)
+	prefixed := encodeString(t, buildSimpleTimeline(t), func(enc *Encoder) {
+		setup(enc)
+		enc.SetIDPrefix("tl1-")
+	})
+
+	ids := regexp.MustCompile(`id="([^"]*)"`).FindAllStringSubmatch(prefixed, -1)
+	if len(ids) == 0 {
+		t.Fatal("SVG has no ids")
+	}
+	for _, id := range ids {
+		if !strings.HasPrefix(id[1], "tl1-") {
+			t.Errorf("id %q missing prefix", id[1])
+		}
+	}
+
+	if strings.ReplaceAll(prefixed, `id="tl1-`, `id="`) != plain {
+		t.Error("Prefix should change nothing but the ids")
+	}
+}
+
+func TestCompactOutput(t *testing.T) {
+	pretty := encodeString(t, buildSimpleTimeline(t), nil)
+	compact := encodeString(t, buildSimpleTimeline(t), func(enc *Encoder) {
+		enc.SetPretty(false)
+	})
+
+	if len(compact) >= len(pretty) {
+		t.Errorf("Compact output (%d bytes) is not smaller than pretty output (%d bytes)", len(compact), len(pretty))
+	}
+	if strings.Contains(compact, ">\n") || strings.Contains(compact, "\n  ") {
+		t.Error("Compact output contains line breaks or indentation between elements")
+	}
+	if !strings.HasPrefix(compact, `<?xml version="1.0" encoding="UTF-8"?><svg `) || !strings.HasSuffix(compact, "</svg>") {
+		t.Error("Compact output is not a complete document")
+	}
+
+	// Text content, such as multi-line tooltips, is preserved
+	if !strings.Contains(compact, "<title>Test Clip\nDuration: 10.0s") {
+		t.Error("Compact output altered tooltip text")
+	}
+}
+
+func TestInlineMode(t *testing.T) {
+	svg := encodeString(t, buildSimpleTimeline(t), nil)
+	if !strings.HasPrefix(svg, "<?xml ") {
+		t.Error("Standalone output should start with the XML declaration")
+	}
+
+	svg = encodeString(t, buildSimpleTimeline(t), func(enc *Encoder) {
+		enc.SetInlineMode(true)
+	})
+	if !strings.HasPrefix(svg, `<svg xmlns="http://www.w3.org/2000/svg" `) {
+		t.Errorf("Inline output should start with the svg root, got %q", svg[:40])
+	}
+	if strings.Contains(svg, "<?xml") {
+		t.Error("Inline output contains the XML declaration")
+	}
+}
+
+func TestResponsive(t *testing.T) {
+	svg := encodeString(t, buildSimpleTimeline(t), func(enc *Encoder) {
+		enc.SetSize(800, 300)
+		enc.SetResponsive(true)
+	})
+
+	if !strings.Contains(svg, `<svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 800 300" preserveAspectRatio="xMidYMid meet">`) {
+		t.Error("Responsive SVG header should have only a viewBox")
+	}
+	if strings.Contains(svg, `width="800"`) || strings.Contains(svg, `height="300"`) {
+		t.Error("Responsive SVG should not have a fixed size")
+	}
+}
+
+// buildLargeTimeline creates a timeline with one video track holding n
+// one-second clips.
+func buildLargeTimeline(b *testing.B, n int) *gotio.Timeline {
+	b.Helper()
+
+	timeline := gotio.NewTimeline("Large", nil, nil)
+	track := gotio.NewTrack("V1", nil, gotio.TrackKindVideo, nil, nil)
+	for i := 0; i < n; i++ {
+		if err := track.AppendChild(newTestClip(fmt.Sprintf("Shot %d", i), 24, 24)); err != nil {
+			b.Fatalf("Failed to append clip: %v", err)
+		}
+	}
+	if err := timeline.Tracks().AppendChild(track); err != nil {
+		b.Fatalf("Failed to append track: %v", err)
+	}
+	return timeline
+}
+
+func BenchmarkEncodeToFile(b *testing.B) {
+	timeline := buildLargeTimeline(b, 5000)
+	path := filepath.Join(b.TempDir(), "large.svg")
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := EncodeToFile(path, timeline); err != nil {
+			b.Fatalf("EncodeToFile failed: %v", err)
+		}
+	}
+}
+
+func TestUseDefs(t *testing.T) {
+	build := func() *gotio.Timeline {
+		timeline := gotio.NewTimeline("", nil, nil)
+		track := gotio.NewTrack("V1", nil, gotio.TrackKindVideo, nil, nil)
+		for i := 0; i < 200; i++ {
+			children := []gotio.Composable{
+				newTestClip(fmt.Sprintf("Shot %d", i), 24, 24),
+				gotio.NewGapWithDuration(opentime.NewRationalTime(24, 24)),
+			}
+			for _, child := range children {
+				if err := track.AppendChild(child); err != nil {
+					t.Fatalf("Failed to append child: %v", err)
+				}
+			}
+		}
+		if err := timeline.Tracks().AppendChild(track); err != nil {
+			t.Fatalf("Failed to append track: %v", err)
+		}
+		return timeline
+	}
+
+	plain := encodeString(t, build(), nil)
+	svg := encodeString(t, build(), func(enc *Encoder) {
+		enc.SetUseDefs(true)
+	})
+
+	if len(svg) >= len(plain) {
+		t.Errorf("Output with defs (%d bytes) is not smaller than without (%d bytes)", len(svg), len(plain))
+	}
+	if strings.Count(svg, "<defs>") != 1 || !strings.Contains(svg, `<symbol id="gap-shape"`) {
+		t.Error("SVG missing shape definitions")
+	}
+	if n := strings.Count(svg, `<use href="#gap-shape" `); n != 200 {
+		t.Errorf("Expected 200 gap references, found %d", n)
+	}
+	if n := strings.Count(svg, `class="gap"`); n != 200 {
+		t.Errorf("Expected 200 gaps, found %d", n)
+	}
+	if !strings.Contains(svg, `<use href="#track-bg-shape" `) {
+		t.Error("Track background should reference its shape")
+	}
+}
+
+// BenchmarkEncodeAllocs reports allocations per clip for timelines of
+// increasing size. A constant allocs/clip shows that encoding does no more
+// work per item as the timeline grows.
+func BenchmarkEncodeAllocs(b *testing.B) {
+	for _, n := range []int{100, 1000} {
+		b.Run(fmt.Sprintf("clips=%d", n), func(b *testing.B) {
+			timeline := buildLargeTimeline(b, n)
+			enc := NewEncoder(io.Discard)
+
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if err := enc.Encode(timeline); err != nil {
+					b.Fatalf("Encode failed: %v", err)
+				}
+			}
+			b.StopTimer()
+
+			allocs := testing.AllocsPerRun(1, func() {
+				if err := enc.Encode(timeline); err != nil {
+					b.Fatalf("Encode failed: %v", err)
+				}
+			})
+			b.ReportMetric(allocs/float64(n), "allocs/clip")
+		})
+	}
+}
+
+func TestClipDurationLine(t *testing.T) {
+	svg := encodeString(t, buildSimpleTimeline(t), nil)
+	if strings.Contains(svg, `class="clip-duration"`) {
+		t.Error("Clip duration should be hidden by default")
+	}
+
+	svg = encodeString(t, buildSimpleTimeline(t), func(enc *Encoder) {
+		enc.SetAutoHeight(true)
+		enc.SetShowClipDuration(true)
+	})
+
+	center := float64(MarginTop+RulerHeight) + TrackHeight/2.0
+	fill := contrastColor(VideoTrackColor)
+	if !strings.Contains(svg, fmt.Sprintf(`y="%.2f" text-anchor="middle" class="clip-label" style="fill: %s" dominant-baseline="middle">Test Clip<`, center-(SmallFontSize+labelLineGap)/2, fill)) {
+		t.Error("Clip name should be drawn above center")
+	}
+	if !strings.Contains(svg, fmt.Sprintf(`y="%.2f" text-anchor="middle" class="clip-duration" style="fill: %s" dominant-baseline="middle">10.0s<`, center+(SmallFontSize+labelLineGap)/2, fill)) {
+		t.Error("Clip duration should be drawn below center")
+	}
+
+	// No room for a second line
+	svg = encodeString(t, buildSimpleTimeline(t), func(enc *Encoder) {
+		enc.SetShowClipDuration(true)
+		if err := WithTrackHeight(20)(enc); err != nil {
+			t.Fatalf("WithTrackHeight failed: %v", err)
+		}
+	})
+	if strings.Contains(svg, `class="clip-duration"`) {
+		t.Error("Clip duration drawn in a clip too short for it")
+	}
+	if !strings.Contains(svg, ">Test Clip</text>") {
+		t.Error("Clip name should still be drawn")
+	}
+}
+
+func TestMediaNameLine(t *testing.T) {
+	sr := opentime.NewTimeRange(opentime.NewRationalTime(0, 24), opentime.NewRationalTime(48, 24))
+	clips := []*gotio.Clip{
+		gotio.NewClip("Named", gotio.NewExternalReference("Interview A", "file:///media/a.mov", nil, nil), &sr, nil, nil, nil, "", nil),
+		gotio.NewClip("Unnamed", gotio.NewExternalReference("", "https://example.com/media/b.mov?v=2", nil, nil), &sr, nil, nil, nil, "", nil),
+		gotio.NewClip("Missing", gotio.NewMissingReference("Lost", nil, nil), &sr, nil, nil, nil, "", nil),
+	}
+
+	timeline := gotio.NewTimeline("Media Names", nil, nil)
+	track := gotio.NewTrack("V1", nil, gotio.TrackKindVideo, nil, nil)
+	for _, clip := range clips {
+		if err := track.AppendChild(clip); err != nil {
+			t.Fatalf("Failed to append clip: %v", err)
+		}
+	}
+	if err := timeline.Tracks().AppendChild(track); err != nil {
+		t.Fatalf("Failed to append track: %v", err)
+	}
+
+	svg := encodeString(t, timeline, nil)
+	if strings.Contains(svg, `class="clip-media"`) {
+		t.Error("Media name should be hidden by default")
+	}
+
+	svg = encodeString(t, timeline, func(enc *Encoder) {
+		enc.SetAutoHeight(true)
+		enc.SetShowMediaName(true)
+	})
+	if n := strings.Count(svg, `class="clip-media"`); n != 2 {
+		t.Errorf("Expected 2 media names, found %d", n)
+	}
+	if !strings.Contains(svg, `dominant-baseline="middle">Interview A<`) {
+		t.Error("Media name should come from the reference name")
+	}
+	if !strings.Contains(svg, `dominant-baseline="middle">b.mov<`) {
+		t.Error("Unnamed media should be named after its target URL")
+	}
+	if strings.Contains(svg, ">Lost<") {
+		t.Error("Missing media should show no media name")
+	}
+	if !strings.Contains(svg, "opacity: 0.7;") {
+		t.Error("CSS missing dimmed media name style")
+	}
+}
+
+func TestCornerRadius(t *testing.T) {
+	timeline := gotio.NewTimeline("Rounded", nil, nil)
+	track := gotio.NewTrack("V1", nil, gotio.TrackKindVideo, nil, nil)
+	children := []gotio.Composable{
+		newTestClip("Shot 1", 48, 24),
+		gotio.NewGapWithDuration(opentime.NewRationalTime(24, 24)),
+		newTestClip("Shot 2", 48, 24),
+	}
+	for _, child := range children {
+		if err := track.AppendChild(child); err != nil {
+			t.Fatalf("Failed to append child: %v", err)
+		}
+	}
+	if err := timeline.Tracks().AppendChild(track); err != nil {
+		t.Fatalf("Failed to append track: %v", err)
+	}
+
+	svg := encodeString(t, timeline, nil)
+	if strings.Contains(svg, ` rx="`) {
+		t.Error("Corners should be square by default")
+	}
+
+	svg = encodeString(t, timeline, func(enc *Encoder) {
+		enc.SetClipCornerRadius(4)
+	})
+	if n := strings.Count(svg, `class="clip" rx="4.00" ry="4.00"`); n != 2 {
+		t.Errorf("Expected 2 rounded clips, found %d", n)
+	}
+	if strings.Contains(svg, `class="gap" rx=`) || strings.Contains(svg, `class="track-bg" rx=`) {
+		t.Error("Clip radius should not round gaps or track backgrounds")
+	}
+
+	svg = encodeString(t, timeline, func(enc *Encoder) {
+		enc.SetGapCornerRadius(3)
+		enc.SetTrackCornerRadius(6)
+		enc.SetUseDefs(true)
+	})
+	if strings.Contains(svg, `class="clip" rx=`) {
+		t.Error("Gap and track radii should not round clips")
+	}
+	if !strings.Contains(svg, `class="gap" rx="3.00" ry="3.00"`) {
+		t.Error("Gaps should be rounded")
+	}
+	if !strings.Contains(svg, `class="track-bg" rx="6.00" ry="6.00"`) {
+		t.Error("Track backgrounds should be rounded")
+	}
+	if strings.Contains(svg, "<defs>") {
+		t.Error("Rounded shapes should not be drawn from symbols")
+	}
+}
+
+func TestClipColorFunc(t *testing.T) {
+	timeline := gotio.NewTimeline("Status", nil, nil)
+	track := gotio.NewTrack("V1", nil, gotio.TrackKindVideo, nil, nil)
+	sr := opentime.NewTimeRange(opentime.NewRationalTime(0, 24), opentime.NewRationalTime(24, 24))
+	for _, status := range []string{"approved", "pending", ""} {
+		md := gotio.AnyDictionary{"status": status}
+		clip := gotio.NewClip("Shot "+status, nil, &sr, md, nil, nil, "", nil)
+		if err := track.AppendChild(clip); err != nil {
+			t.Fatalf("Failed to append clip: %v", err)
+		}
+	}
+	if err := timeline.Tracks().AppendChild(track); err != nil {
+		t.Fatalf("Failed to append track: %v", err)
+	}
+
+	colors := map[string]string{"approved": "#00FF00", "pending": "#FFFF00"}
+	svg := encodeString(t, timeline, func(enc *Encoder) {
+		enc.SetClipColorFunc(func(clip *gotio.Clip) string {
+			status, _ := clip.Metadata()["status"].(string)
+			return colors[status]
+		})
+	})
+
+	for _, want := range []string{
+		`fill="#00FF00" stroke="#333333" id="clip-Shot_approved"`,
+		`fill="#FFFF00" stroke="#333333" id="clip-Shot_pending"`,
+		`fill="` + DefaultTheme().VideoTrack + `" stroke="#333333" id="clip-Shot_"`,
+	} {
+		if !strings.Contains(svg, want) {
+			t.Errorf("SVG missing clip colored %s", want)
+		}
+	}
+}
+
+func TestClipColorFuncEscaped(t *testing.T) {
+	svg := encodeString(t, buildSimpleTimeline(t), func(enc *Encoder) {
+		enc.SetClipColorFunc(func(*gotio.Clip) string {
+			return `red" onload="alert(1)`
+		})
+	})
+
+	if strings.Contains(svg, `onload="`) {
+		t.Error("Clip color should not inject attributes")
+	}
+	if !strings.Contains(svg, `fill="red&quot; onload=&quot;alert(1)"`) {
+		t.Error("Clip color not escaped")
+	}
+	if err := xml.Unmarshal([]byte(svg), new(struct{})); err != nil {
+		t.Errorf("SVG is not well-formed: %v", err)
+	}
+}
+
+func TestMinClipWidth(t *testing.T) {
+	// A one-frame clip is about 0.5px wide at the default scale
+	timeline := gotio.NewTimeline("Short", nil, nil)
+	track := gotio.NewTrack("V1", nil, gotio.TrackKindVideo, nil, nil)
+	for _, clip := range []*gotio.Clip{newTestClip("Long", 2400, 24), newTestClip("Flash", 1, 24)} {
+		if err := track.AppendChild(clip); err != nil {
+			t.Fatalf("Failed to append clip: %v", err)
+		}
+	}
+	if err := timeline.Tracks().AppendChild(track); err != nil {
+		t.Fatalf("Failed to append track: %v", err)
+	}
+
+	var widths []string
+	svg := encodeString(t, timeline, nil)
+	re := regexp.MustCompile(`width="([0-9.]+)" height="[0-9.]+" fill="[^"]*" stroke="[^"]*" id="clip-Flash"`)
+	if m := re.FindStringSubmatch(svg); m != nil {
+		widths = append(widths, m[1])
+	}
+	if n := strings.Count(svg, `class="min-width"`); n != 1 {
+		t.Errorf("Expected 1 widened clip accent, found %d", n)
+	}
+
+	svg = encodeString(t, timeline, func(enc *Encoder) {
+		enc.SetMinClipWidth(12)
+	})
+	if m := re.FindStringSubmatch(svg); m != nil {
+		widths = append(widths, m[1])
+	}
+	if !slices.Equal(widths, []string{"5.00", "12.00"}) {
+		t.Errorf("Expected short clip widths [5.00 12.00], got %v", widths)
+	}
+
+	svg = encodeString(t, timeline, func(enc *Encoder) {
+		enc.SetMinClipWidth(0)
+	})
+	if strings.Contains(svg, `class="min-width"`) {
+		t.Error("Clips drawn at their true width should not be marked")
+	}
+}
+
+func TestFontFamily(t *testing.T) {
+	svg := encodeString(t, buildSimpleTimeline(t), nil)
+	if !strings.Contains(svg, "font-family: "+DefaultFontFamily+";") {
+		t.Error("CSS missing default font family")
+	}
+
+	var enc *Encoder
+	svg = encodeString(t, buildSimpleTimeline(t), func(e *Encoder) {
+		enc = e
+		if err := e.SetFontFamily(` "Corporate Sans", Helvetica, sans-serif `); err != nil {
+			t.Fatalf("SetFontFamily failed: %v", err)
+		}
+	})
+	if !strings.Contains(svg, `font-family: "Corporate Sans", Helvetica, sans-serif;`) {
+		t.Error("CSS missing configured font family")
+	}
+	if strings.Contains(svg, "Arial") {
+		t.Error("Default font family should be replaced everywhere")
+	}
+
+	for _, family := range []string{"", "   ", "Evil</style><script>", "a; fill: red", "a } text { fill: red", "A &amp; B", "Line\nBreak"} {
+		if err := enc.SetFontFamily(family); err == nil {
+			t.Errorf("Expected error for font family %q", family)
+		}
+	}
+	if enc.fontFamily != `"Corporate Sans", Helvetica, sans-serif` {
+		t.Errorf("Invalid font family should not replace the current one, got %q", enc.fontFamily)
+	}
+}
+
+func TestHoverEffects(t *testing.T) {
+	timeline := buildSimpleTimeline(t)
+	plain := encodeString(t, timeline, nil)
+	if strings.Contains(plain, ":hover") {
+		t.Error("Hover effects should be off by default")
+	}
+
+	svg := encodeString(t, timeline, func(enc *Encoder) {
+		enc.SetHoverEffects(true)
+	})
+	if !strings.Contains(svg, ".clip:hover {\n      stroke-width: 3;\n      filter: brightness(1.15);\n    }") {
+		t.Error("Hover rule missing from the styles")
+	}
+	if strings.Replace(svg, hoverCSS, "", 1) != plain {
+		t.Error("Hover effects should only add the hover rule")
+	}
+}
+
+func TestFontSizes(t *testing.T) {
+	svg := encodeString(t, buildSimpleTimeline(t), nil)
+	for _, want := range []string{
+		fmt.Sprintf(".track-label {\n      font-size: %dpx;", FontSize),
+		fmt.Sprintf(".clip-label {\n      font-size: %dpx;", SmallFontSize),
+		fmt.Sprintf(".ruler-text {\n      font-size: %dpx;", SmallFontSize),
+	} {
+		if !strings.Contains(svg, want) {
+			t.Errorf("CSS missing default %q", want)
+		}
+	}
+
+	var enc *Encoder
+	svg = encodeString(t, buildSimpleTimeline(t), func(e *Encoder) {
+		enc = e
+		e.SetAutoHeight(true)
+		e.SetShowClipDuration(true)
+		if err := e.SetFontSizes(24, 20, 16); err != nil {
+			t.Fatalf("SetFontSizes failed: %v", err)
+		}
+	})
+	for _, want := range []string{
+		".track-label {\n      font-size: 24px;",
+		".clip-label {\n      font-size: 20px;",
+		".clip-duration {\n      font-size: 19px;",
+		".ruler-text {\n      font-size: 16px;",
+	} {
+		if !strings.Contains(svg, want) {
+			t.Errorf("CSS missing %q", want)
+		}
+	}
+
+	// Clip label lines are spaced for the larger font
+	center := float64(MarginTop+RulerHeight) + TrackHeight/2.0
+	if !strings.Contains(svg, fmt.Sprintf(`y="%.2f" text-anchor="middle" class="clip-duration"`, center+(20+labelLineGap)/2.0)) {
+		t.Error("Clip duration should be spaced for the clip font size")
+	}
+
+	if err := enc.SetFontSizes(0, 10, 10); err == nil {
+		t.Error("Expected error for zero font size")
+	}
+	if enc.trackFontSize != 24 {
+		t.Error("Invalid font sizes should not be applied")
+	}
+}
+
+func TestBackground(t *testing.T) {
+	svg := encodeString(t, buildSimpleTimeline(t), nil)
+	want := fmt.Sprintf(`<rect x="0.00" y="0.00" width="%d.00" height="%d.00" fill="%s" id="background" class="background" />`, DefaultWidth, DefaultHeight, BackgroundColor)
+	if !strings.Contains(svg, want) {
+		t.Fatal("SVG missing full-canvas background")
+	}
+	if bg, first := strings.Index(svg, want), strings.Index(svg, "<rect "); bg != first {
+		t.Error("Background should be the first rectangle")
+	}
+	if bg, style := strings.Index(svg, want), strings.Index(svg, "<style>"); bg > style {
+		t.Error("Background should come before all other content")
+	}
+
+	svg = encodeString(t, buildSimpleTimeline(t), func(enc *Encoder) {
+		enc.SetTheme(DarkTheme())
+	})
+	if !strings.Contains(svg, `fill="#1E1E1E" id="background"`) {
+		t.Error("Background should use the theme's background color")
+	}
+
+	svg = encodeString(t, buildSimpleTimeline(t), func(enc *Encoder) {
+		enc.SetTheme(DarkTheme())
+		enc.SetBackground("#102030")
+	})
+	if !strings.Contains(svg, `fill="#102030" id="background"`) {
+		t.Error("Background color should override the theme")
+	}
+
+	svg = encodeString(t, buildSimpleTimeline(t), func(enc *Encoder) {
+		enc.SetTransparentBackground(true)
+	})
+	if strings.Contains(svg, `class="background"`) {
+		t.Error("Transparent background should not be drawn")
+	}
+}
+
+func TestBackgroundEscaped(t *testing.T) {
+	svg := encodeString(t, buildSimpleTimeline(t), func(enc *Encoder) {
+		enc.SetBackground(`a"b`)
+	})
+	if !strings.Contains(svg, `fill="a&quot;b" id="background"`) {
+		t.Error("Background color not escaped")
+	}
+	if err := xml.Unmarshal([]byte(svg), new(struct{})); err != nil {
+		t.Errorf("SVG is not well-formed: %v", err)
+	}
+}
+
+func TestRenderEmpty(t *testing.T) {
+	empty := gotio.NewTimeline("Placeholder", nil, nil)
+
+	var buf bytes.Buffer
+	if err := NewEncoder(&buf).Encode(empty); err == nil {
+		t.Error("Expected error for empty timeline by default")
+	}
+
+	svg := encodeString(t, empty, func(enc *Encoder) {
+		enc.SetRenderEmpty(true)
+	})
+	if !strings.Contains(svg, `class="background"`) {
+		t.Error("Empty timeline should draw the background")
+	}
+	if !strings.Contains(svg, `class="timeline-title" dominant-baseline="middle">Placeholder</text>`) {
+		t.Error("Empty timeline should draw the title")
+	}
+	want := fmt.Sprintf(`<text x="%d.00" y="%d.00" text-anchor="middle" id="empty-message" class="empty-message" dominant-baseline="middle">Empty timeline</text>`, DefaultWidth/2, DefaultHeight/2)
+	if !strings.Contains(svg, want) {
+		t.Error("Empty timeline should draw a centered message")
+	}
+	if !strings.HasSuffix(strings.TrimSpace(svg), "</svg>") {
+		t.Error("Empty timeline SVG should be complete")
+	}
+
+	// A timeline whose only tracks are filtered out is empty too
+	timeline := buildSimpleTimeline(t)
+	enc := NewEncoder(&buf)
+	enc.SetRenderEmpty(true)
+	enc.SetTrackKindFilter(gotio.TrackKindAudio)
+	stats, err := enc.EncodeWithStats(timeline)
+	if err != nil {
+		t.Fatalf("Failed to encode filtered timeline: %v", err)
+	}
+	if stats.Tracks != 0 || stats.Width != DefaultWidth || stats.Height != DefaultHeight {
+		t.Errorf("Unexpected stats for empty render: %+v", stats)
+	}
+}
+
+func TestClipWithoutSourceRange(t *testing.T) {
+	available := opentime.NewTimeRange(opentime.NewRationalTime(0, 24), opentime.NewRationalTime(72, 24))
+	untrimmed := gotio.NewClip("Untrimmed", gotio.NewExternalReference("", "file:///media/u.mov", &available, nil), nil, nil, nil, nil, "", nil)
+
+	timeline := gotio.NewTimeline("Untrimmed", nil, nil)
+	track := gotio.NewTrack("V1", nil, gotio.TrackKindVideo, nil, nil)
+	for _, clip := range []*gotio.Clip{newTestClip("Before", 48, 24), untrimmed, newTestClip("After", 24, 24)} {
+		if err := track.AppendChild(clip); err != nil {
+			t.Fatalf("Failed to append clip: %v", err)
+		}
+	}
+	if err := timeline.Tracks().AppendChild(track); err != nil {
+		t.Fatalf("Failed to append track: %v", err)
+	}
+
+	layout, err := NewEncoder(io.Discard).EncodeWithLayout(timeline)
+	if err != nil {
+		t.Fatalf("Failed to encode timeline: %v", err)
+	}
+	items := layout.Tracks[0].Items
+	if len(items) != 3 {
+		t.Fatalf("Expected 3 items, found %d", len(items))
+	}
+	if items[1].Name != "Untrimmed" || items[1].Start != 2 || items[1].Duration != 3 {
+		t.Errorf("Untrimmed clip should use its available range, got %+v", items[1])
+	}
+	if items[2].Start != 5 {
+		t.Errorf("Clip after the untrimmed clip should start at 5s, got %v", items[2].Start)
+	}
+}
+
+func TestChildRangeFallback(t *testing.T) {
+	// A clip with no duration at all is skipped, and the items after it
+	// still drawn
+	track := gotio.NewTrack("V1", nil, gotio.TrackKindVideo, nil, nil)
+	children := []gotio.Composable{
+		newTestClip("Before", 24, 24),
+		gotio.NewClip("Broken", nil, nil, nil, nil, nil, "", nil),
+		newTestClip("After", 48, 24),
+	}
+	for _, child := range children {
+		if err := track.AppendChild(child); err != nil {
+			t.Fatalf("Failed to append child: %v", err)
+		}
+	}
+
+	var cursor opentime.RationalTime
+	var starts []float64
+	for i := range children {
+		rng, ok := childRange(track, i, cursor)
+		if !ok {
+			continue
+		}
+		cursor = rng.EndTimeExclusive()
+		starts = append(starts, rng.StartTime().ToSeconds())
+	}
+	if !slices.Equal(starts, []float64{0, 1}) {
+		t.Errorf("Expected items at [0 1], got %v", starts)
+	}
+}
+
+// cancelWriter cancels a context on its first write.
+type cancelWriter struct {
+	cancel context.CancelFunc
+	writes int
+}
+
+func (w *cancelWriter) Write(p []byte) (int, error) {
+	w.writes++
+	w.cancel()
+	return len(p), nil
+}
+
+func TestEncodeContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	var buf bytes.Buffer
+	if err := NewEncoder(&buf).EncodeContext(ctx, buildSimpleTimeline(t)); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled for canceled context, got %v", err)
+	}
+
+	// Cancel part way through a large track
+	timeline := gotio.NewTimeline("Large", nil, nil)
+	track := gotio.NewTrack("V1", nil, gotio.TrackKindVideo, nil, nil)
+	for i := 0; i < 1000; i++ {
+		if err := track.AppendChild(newTestClip(fmt.Sprintf("Shot %d", i), 24, 24)); err != nil {
+			t.Fatalf("Failed to append clip: %v", err)
+		}
+	}
+	if err := timeline.Tracks().AppendChild(track); err != nil {
+		t.Fatalf("Failed to append track: %v", err)
+	}
+
+	ctx, cancel = context.WithCancel(context.Background())
+	defer cancel()
+	w := &cancelWriter{cancel: cancel}
+	if err := NewEncoder(w).EncodeContext(ctx, timeline); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled when canceled during encoding, got %v", err)
+	}
+	if w.writes > 3 {
+		t.Errorf("Encoding should stop soon after cancellation, saw %d writes", w.writes)
+	}
+
+	if err := NewEncoder(io.Discard).EncodeContext(context.Background(), timeline); err != nil {
+		t.Errorf("EncodeContext failed: %v", err)
+	}
+}
+
+func TestConcurrentEncode(t *testing.T) {
+	timeline := buildSimpleTimeline(t)
+	enc := NewEncoder(io.Discard)
+	enc.SetIDPrefix("tl-")
+	enc.SetRecordLayout(true)
+
+	var want bytes.Buffer
+	if err := enc.EncodeTo(&want, timeline); err != nil {
+		t.Fatalf("EncodeTo failed: %v", err)
+	}
+
+	const workers = 8
+	outputs := make([]bytes.Buffer, workers)
+	errs := make(chan error, workers)
+	var wg sync.WaitGroup
+	for i := range outputs {
+		wg.Add(1)
+		go func(w io.Writer) {
+			defer wg.Done()
+			errs <- enc.EncodeTo(w, timeline)
+		}(&outputs[i])
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			t.Fatalf("Concurrent EncodeTo failed: %v", err)
+		}
+	}
+	for i := range outputs {
+		if outputs[i].String() != want.String() {
+			t.Errorf("Output %d differs from a serial encode", i)
+		}
+	}
+	if len(enc.LastLayout()) != 1 {
+		t.Errorf("Expected the last layout to hold 1 clip, got %d", len(enc.LastLayout()))
+	}
+}
+
+func TestSetWriter(t *testing.T) {
+	var first, second bytes.Buffer
+	enc := NewEncoder(&first, WithSize(800, 400))
+	enc.SetTheme(DarkTheme())
+
+	if err := enc.Encode(buildSimpleTimeline(t)); err != nil {
+		t.Fatalf("Encode failed: %v", err)
+	}
+	enc.SetWriter(&second)
+	if err := enc.Encode(buildSimpleTimeline(t)); err != nil {
+		t.Fatalf("Encode failed: %v", err)
+	}
+
+	if first.String() != second.String() {
+		t.Error("Reused encoder should produce the same output, with ids starting afresh")
+	}
+	if !strings.Contains(second.String(), `width="800" height="400"`) {
+		t.Error("Reused encoder should keep its configuration")
+	}
+	if strings.Count(first.String(), "</svg>") != 1 {
+		t.Error("Output should go only to the current writer")
+	}
+}
+
+func TestViewRange(t *testing.T) {
+	timeline := gotio.NewTimeline("Scenes", nil, nil)
+	track := gotio.NewTrack("V1", nil, gotio.TrackKindVideo, nil, nil)
+	for i := 1; i <= 10; i++ {
+		if err := track.AppendChild(newTestClip(fmt.Sprintf("Shot %d", i), 24, 24)); err != nil {
+			t.Fatalf("Failed to append clip: %v", err)
+		}
+	}
+	if err := timeline.Tracks().AppendChild(track); err != nil {
+		t.Fatalf("Failed to append track: %v", err)
+	}
+
+	view := opentime.NewTimeRange(opentime.NewRationalTime(60, 24), opentime.NewRationalTime(60, 24)) // 2.5s to 5s
+	svg := encodeString(t, timeline, func(enc *Encoder) {
+		enc.SetViewRange(view)
+		enc.SetPlayhead(opentime.NewRationalTime(72, 24))
+	})
+
+	for _, name := range []string{"Shot_1", "Shot_2", "Shot_6", "Shot_10"} {
+		if strings.Contains(svg, `id="clip-`+name+`"`) {
+			t.Errorf("Clip %s outside the view should be left out", name)
+		}
+	}
+
+	// Shot 3 straddles the start of the view and is cut off by the clip path
+	scale := float64(DefaultWidth-MarginLeft-MarginRight) / 2.5
+	want := fmt.Sprintf(`<rect x="%.2f" y=`, float64(MarginLeft)-0.5*scale)
+	i := strings.Index(svg, `id="clip-Shot_3"`)
+	if i < 0 {
+		t.Fatal("Clip straddling the view should be drawn")
+	}
+	if !strings.Contains(svg[strings.LastIndex(svg[:i], "<rect "):i], want) {
+		t.Error("Clip straddling the view should keep its true position")
+	}
+	if !strings.Contains(svg, `<clipPath id="view-clip">`) || !strings.Contains(svg, `<g clip-path="url(#view-clip)">`) {
+		t.Error("Items should be clipped to the view")
+	}
+	if !strings.Contains(svg, `dominant-baseline="middle">2.5s</text>`) {
+		t.Error("Ruler should start at the view's start time")
+	}
+	if !strings.Contains(svg, fmt.Sprintf(`<line x1="%.2f"`, float64(MarginLeft)+0.5*scale)) {
+		t.Error("Playhead should be placed within the view")
+	}
+
+	var buf bytes.Buffer
+	enc := NewEncoder(&buf)
+	enc.SetViewRange(opentime.NewTimeRange(opentime.NewRationalTime(0, 24), opentime.NewRationalTime(0, 24)))
+	if err := enc.Encode(timeline); err == nil {
+		t.Error("Expected error for an empty view range")
+	}
+}
+
+func TestClipAriaLabels(t *testing.T) {
+	timeline := gotio.NewTimeline("Accessible", nil, nil)
+	track := gotio.NewTrack("V1", nil, gotio.TrackKindVideo, nil, nil)
+	for _, clip := range []*gotio.Clip{newTestClip("Shot <1>", 48, 24), newTestClip("", 24, 24)} {
+		if err := track.AppendChild(clip); err != nil {
+			t.Fatalf("Failed to append clip: %v", err)
+		}
+	}
+	if err := timeline.Tracks().AppendChild(track); err != nil {
+		t.Fatalf("Failed to append track: %v", err)
+	}
+
+	svg := encodeString(t, timeline, nil)
+	if !strings.Contains(svg, `aria-label="Shot &lt;1&gt;, 0.0s to 2.0s"`) {
+		t.Error("Clip aria-label missing or not escaped")
+	}
+	if !strings.Contains(svg, `aria-label="Clip, 2.0s to 3.0s"`) {
+		t.Error("Unnamed clip aria-label missing")
+	}
+
+	svg = encodeString(t, timeline, func(enc *Encoder) {
+		enc.SetTimeFormat(TimeFormatFrames)
+	})
+	if !strings.Contains(svg, `aria-label="Clip, 48 to 72"`) {
+		t.Error("Clip aria-label should use the time format")
+	}
+}
@@ -0,0 +1,84 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Contributors to the OpenTimelineIO project
+
+package svg
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"strings"
+)
+
+// fontFormat describes a font file format by its MIME type and the name
+// used for it in an @font-face src descriptor.
+type fontFormat struct {
+	mime string
+	name string
+}
+
+// fontSignatures maps the leading bytes of font files to their format.
+var fontSignatures = []struct {
+	magic  []byte
+	format fontFormat
+}{
+	{[]byte("wOF2"), fontFormat{"font/woff2", "woff2"}},
+	{[]byte("wOFF"), fontFormat{"font/woff", "woff"}},
+	{[]byte{0x00, 0x01, 0x00, 0x00}, fontFormat{"font/ttf", "truetype"}},
+	{[]byte("true"), fontFormat{"font/ttf", "truetype"}},
+	{[]byte("OTTO"), fontFormat{"font/otf", "opentype"}},
+}
+
+// detectFontFormat returns the format of a font file from its first bytes.
+func detectFontFormat(data []byte) (fontFormat, bool) {
+	for _, sig := range fontSignatures {
+		if bytes.HasPrefix(data, sig.magic) {
+			return sig.format, true
+		}
+	}
+	return fontFormat{}, false
+}
+
+// embeddedFont is a font written into the SVG as a data URI.
+type embeddedFont struct {
+	name   string
+	format fontFormat
+	data   string // base64 encoded
+}
+
+// EmbedFont embeds a WOFF2, WOFF, TrueType or OpenType font in the SVG
+// under the given name and uses it for all text, so text renders the same
+// on systems without the font installed. The font family set with
+// SetFontFamily remains as the fallback. The format is detected from the
+// font data.
+func (e *Encoder) EmbedFont(name string, data []byte) error {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return fmt.Errorf("invalid font name: must not be empty")
+	}
+	for _, r := range name {
+		if r == '"' || r == '\'' || unsafeFontRune(r) {
+			return fmt.Errorf("invalid font name %q: unsupported character %q", name, r)
+		}
+	}
+	format, ok := detectFontFormat(data)
+	if !ok {
+		return fmt.Errorf("failed to embed font %q: unrecognized font format", name)
+	}
+
+	e.font = &embeddedFont{
+		name:   name,
+		format: format,
+		data:   base64.StdEncoding.EncodeToString(data),
+	}
+	return nil
+}
+
+// fontFaceCSS returns the @font-face rule for an embedded font.
+func (f *embeddedFont) fontFaceCSS() string {
+	return fmt.Sprintf(`
+    @font-face {
+      font-family: "%s";
+      src: url(data:%s;base64,%s) format("%s");
+    }`, f.name, f.format.mime, f.data, f.format.name)
+}
@@ -0,0 +1,68 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Contributors to the OpenTimelineIO project
+
+package svg
+
+import (
+	"bytes"
+	"encoding/base64"
+	"strings"
+	"testing"
+)
+
+func TestDetectFontFormat(t *testing.T) {
+	tests := []struct {
+		name string
+		data []byte
+		mime string
+		ok   bool
+	}{
+		{"woff2", []byte("wOF2\x00\x01\x00\x00"), "font/woff2", true},
+		{"woff", []byte("wOFF\x00\x01\x00\x00"), "font/woff", true},
+		{"truetype", []byte{0x00, 0x01, 0x00, 0x00, 0x00, 0x0c}, "font/ttf", true},
+		{"apple truetype", []byte("true\x00\x0c"), "font/ttf", true},
+		{"opentype", []byte("OTTO\x00\x0c"), "font/otf", true},
+		{"png", []byte("\x89PNG\r\n"), "", false},
+		{"empty", nil, "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			format, ok := detectFontFormat(tt.data)
+			if ok != tt.ok || format.mime != tt.mime {
+				t.Errorf("detectFontFormat() = %q, %v; want %q, %v", format.mime, ok, tt.mime, tt.ok)
+			}
+		})
+	}
+}
+
+func TestEmbedFont(t *testing.T) {
+	data := []byte("wOF2 font data")
+
+	var buf bytes.Buffer
+	enc := NewEncoder(&buf)
+	if err := enc.EmbedFont("Brand Sans", data); err != nil {
+		t.Fatalf("EmbedFont failed: %v", err)
+	}
+	if err := enc.Encode(buildSimpleTimeline(t)); err != nil {
+		t.Fatalf("Failed to encode timeline: %v", err)
+	}
+	svg := buf.String()
+
+	uri := "url(data:font/woff2;base64," + base64.StdEncoding.EncodeToString(data) + `) format("woff2")`
+	if !strings.Contains(svg, "@font-face") || !strings.Contains(svg, uri) {
+		t.Error("SVG missing embedded font")
+	}
+	if !strings.Contains(svg, `font-family: "Brand Sans", `+DefaultFontFamily+";") {
+		t.Error("Text should use the embedded font with the default family as fallback")
+	}
+
+	for _, name := range []string{"", `Bad"Name`, "Bad</style>"} {
+		if err := enc.EmbedFont(name, data); err == nil {
+			t.Errorf("Expected error for font name %q", name)
+		}
+	}
+	if err := enc.EmbedFont("Unknown", []byte("not a font")); err == nil {
+		t.Error("Expected error for unrecognized font data")
+	}
+}
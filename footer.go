@@ -0,0 +1,61 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Contributors to the OpenTimelineIO project
+
+package svg
+
+import (
+	"runtime/debug"
+	"time"
+)
+
+// ToolName is the name written in the footer.
+const ToolName = "otio-svg"
+
+// modulePath is the import path of this module, used to find its version
+// in the build info.
+const modulePath = "github.com/Avalanche-io/otio-svg"
+
+// footerOffset is the distance from the bottom of the canvas to the middle
+// of the footer text.
+const footerOffset = 10
+
+// toolVersion returns the version of this module recorded in the build
+// info, or "devel" for local builds.
+func toolVersion() string {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return "devel"
+	}
+	mod := &info.Main
+	for _, dep := range info.Deps {
+		if dep.Path == modulePath {
+			mod = dep
+			break
+		}
+	}
+	if mod.Path != modulePath || mod.Version == "" || mod.Version == "(devel)" {
+		return "devel"
+	}
+	if mod.Replace != nil && mod.Replace.Version != "" {
+		return mod.Replace.Version
+	}
+	return mod.Version
+}
+
+// footerText returns the footer line naming the tool, its version and the
+// time of encoding in UTC.
+func footerText(now time.Time) string {
+	return ToolName + " " + toolVersion() + " · " + now.UTC().Format("2006-01-02 15:04:05 MST")
+}
+
+// drawFooter draws the footer line in the bottom margin, right-aligned with
+// the tracks.
+func (e *Encoder) drawFooter(builder *SVGBuilder, l *layout) error {
+	now := time.Now
+	if e.clock != nil {
+		now = e.clock
+	}
+	x := float64(l.width - e.marginRight)
+	y := float64(l.height - footerOffset)
+	return builder.WriteText(x, y, footerText(now()), "end", builder.UniqueID("footer"), "footer-text")
+}
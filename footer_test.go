@@ -0,0 +1,47 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Contributors to the OpenTimelineIO project
+
+package svg
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFooter(t *testing.T) {
+	svg := encodeString(t, buildSimpleTimeline(t), nil)
+	if strings.Contains(svg, `class="footer-text"`) {
+		t.Error("Footer should be hidden by default")
+	}
+
+	clock := func() time.Time {
+		return time.Date(2024, 3, 9, 14, 30, 5, 0, time.FixedZone("CET", 3600))
+	}
+	svg = encodeString(t, buildSimpleTimeline(t), func(enc *Encoder) {
+		enc.SetShowFooter(true)
+		enc.SetClock(clock)
+	})
+
+	want := fmt.Sprintf(`<text x="%d.00" y="%d.00" text-anchor="end" id="footer" class="footer-text" dominant-baseline="middle">%s %s · 2024-03-09 13:30:05 UTC</text>`,
+		DefaultWidth-MarginRight, DefaultHeight-footerOffset, ToolName, toolVersion())
+	if !strings.Contains(svg, want) {
+		t.Errorf("SVG missing footer %q", want)
+	}
+
+	again := encodeString(t, buildSimpleTimeline(t), func(enc *Encoder) {
+		enc.SetShowFooter(true)
+		enc.SetClock(clock)
+	})
+	if again != svg {
+		t.Error("Output with a fixed clock should be deterministic")
+	}
+}
+
+func TestToolVersion(t *testing.T) {
+	// Tests run the module itself, which has no released version
+	if v := toolVersion(); v != "devel" {
+		t.Errorf("toolVersion() = %q, want %q", v, "devel")
+	}
+}
@@ -0,0 +1,70 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Contributors to the OpenTimelineIO project
+
+package svg
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/Avalanche-io/gotio"
+)
+
+// handleHeight is the height of the strip showing a clip's handles.
+const handleHeight = 4.0
+
+// clipHandle is the unused media on one side of a clip, as a strip in
+// layout coordinates.
+type clipHandle struct {
+	x, y, width float64
+	title       string
+}
+
+// clipHandles returns the seconds of media available before and after the
+// part of a clip that is used, or zeros if the clip isn't trimmed or its
+// available range is unknown.
+func clipHandles(clip *gotio.Clip) (head, tail float64) {
+	sr := clip.SourceRange()
+	if sr == nil {
+		return 0, 0
+	}
+	available, err := clip.AvailableRange()
+	if err != nil {
+		return 0, 0
+	}
+	head = sr.StartTime().ToSeconds() - available.StartTime().ToSeconds()
+	tail = available.EndTimeExclusive().ToSeconds() - sr.EndTimeExclusive().ToSeconds()
+	return max(head, 0), max(tail, 0)
+}
+
+// handlesOf returns the strips showing the handles of a clip drawn at x in
+// layout coordinates, along the bottom of the band at y. Strips are cut off
+// at the ends of the time axis.
+func (e *Encoder) handlesOf(l *layout, clip *gotio.Clip, x, y, width, height float64) []clipHandle {
+	head, tail := clipHandles(clip)
+	stripY := y + height - itemPadding - handleHeight
+	left, right := l.contentLeft, l.contentLeft+l.contentWidth
+
+	var handles []clipHandle
+	if x1 := math.Max(x-head*l.timeScale, left); head > 0 && x1 < x {
+		title := fmt.Sprintf("Head handle: %s", formatTimeAs(head, e.timeFormat, l.rate))
+		handles = append(handles, clipHandle{x1, stripY, x - x1, title})
+	}
+	if x2 := math.Min(x+width+tail*l.timeScale, right); tail > 0 && x2 > x+width {
+		title := fmt.Sprintf("Tail handle: %s", formatTimeAs(tail, e.timeFormat, l.rate))
+		handles = append(handles, clipHandle{x + width, stripY, x2 - x - width, title})
+	}
+	return handles
+}
+
+// drawHandles draws the handles of a track's clips, on top of its items so
+// that handles reaching under neighboring clips still show.
+func (e *Encoder) drawHandles(builder *SVGBuilder, l *layout, handles []clipHandle, color string) error {
+	for _, h := range handles {
+		x, y, width, height := l.place(h.x, h.y, h.width, handleHeight)
+		if err := builder.WriteRectWithTitle(x, y, width, height, color, "", "", "handle", h.title); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,94 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Contributors to the OpenTimelineIO project
+
+package svg
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/Avalanche-io/gotio"
+	"github.com/Avalanche-io/gotio/opentime"
+)
+
+// newTrimmedClip returns a clip using frames [start, end) of media that
+// runs from frame 0 to available, at 24 fps.
+func newTrimmedClip(name string, start, end, available float64) *gotio.Clip {
+	ar := opentime.NewTimeRange(opentime.NewRationalTime(0, 24), opentime.NewRationalTime(available, 24))
+	sr := opentime.NewTimeRange(opentime.NewRationalTime(start, 24), opentime.NewRationalTime(end-start, 24))
+	ref := gotio.NewExternalReference("", "file:///media/"+name+".mov", &ar, nil)
+	return gotio.NewClip(name, ref, &sr, nil, nil, nil, "", nil)
+}
+
+func TestClipHandles(t *testing.T) {
+	tests := []struct {
+		name       string
+		clip       *gotio.Clip
+		head, tail float64
+	}{
+		{"both", newTrimmedClip("A", 24, 72, 96), 1, 1},
+		{"head only", newTrimmedClip("A", 48, 96, 96), 2, 0},
+		{"untrimmed", newTrimmedClip("A", 0, 96, 96), 0, 0},
+		{"past the media", newTrimmedClip("A", 0, 120, 96), 0, 0},
+		{"no media", newTestClip("A", 24, 24), 0, 0},
+	}
+
+	for _, tt := range tests {
+		head, tail := clipHandles(tt.clip)
+		if head != tt.head || tail != tt.tail {
+			t.Errorf("%s: clipHandles = %v, %v, want %v, %v", tt.name, head, tail, tt.head, tt.tail)
+		}
+	}
+}
+
+func TestShowHandles(t *testing.T) {
+	timeline := gotio.NewTimeline("Handles", nil, nil)
+	track := gotio.NewTrack("V1", nil, gotio.TrackKindVideo, nil, nil)
+	for _, item := range []gotio.Composable{
+		gotio.NewGapWithDuration(opentime.NewRationalTime(24, 24)),
+		newTrimmedClip("Shot_1", 24, 72, 96),
+		newTrimmedClip("Shot_2", 0, 48, 72),
+	} {
+		if err := track.AppendChild(item); err != nil {
+			t.Fatalf("Failed to append item: %v", err)
+		}
+	}
+	if err := timeline.Tracks().AppendChild(track); err != nil {
+		t.Fatalf("Failed to append track: %v", err)
+	}
+
+	if strings.Contains(encodeString(t, timeline, nil), `class="handle"`) {
+		t.Error("Handles should be off by default")
+	}
+
+	svg := encodeString(t, timeline, func(enc *Encoder) {
+		enc.SetShowHandles(true)
+	})
+	if n := strings.Count(svg, `class="handle"`); n != 2 {
+		t.Fatalf("Expected 2 handles, found %d", n)
+	}
+
+	// A second of handle on each side of the first clip, along the bottom
+	// of the track, with the second clip's tail past the end of the
+	// timeline cut off
+	scale := float64(DefaultWidth-MarginLeft-MarginRight) / 5
+	y := float64(MarginTop+RulerHeight+TrackHeight) - itemPadding - handleHeight
+	for _, x := range []float64{MarginLeft, MarginLeft + 3*scale} {
+		want := fmt.Sprintf(`<rect x="%.2f" y="%.2f" width="%.2f" height="%.2f" fill="%s" class="handle">`, x, y, scale, handleHeight, VideoTrackColor)
+		if !strings.Contains(svg, want) {
+			t.Errorf("Handle not drawn, want %s", want)
+		}
+	}
+	if strings.Contains(svg, fmt.Sprintf(`<rect x="%.2f" y="%.2f"`, float64(DefaultWidth-MarginRight), y)) {
+		t.Error("Handle past the end of the timeline should be cut off")
+	}
+	if !strings.Contains(svg, "<title>Head handle: 1.0s</title>") || !strings.Contains(svg, "<title>Tail handle: 1.0s</title>") {
+		t.Error("Handles should be titled with their length")
+	}
+
+	// Handles are drawn over the neighboring clips
+	if strings.LastIndex(svg, `class="handle"`) < strings.Index(svg, `id="clip-Shot_2"`) {
+		t.Error("Handles should be drawn after the track's clips")
+	}
+}
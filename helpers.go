@@ -0,0 +1,59 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Contributors to the OpenTimelineIO project
+
+package svg
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/Avalanche-io/gotio"
+)
+
+// EncodeToString encodes a timeline to SVG and returns it as a string.
+func EncodeToString(t *gotio.Timeline, opts ...Option) (string, error) {
+	var sb strings.Builder
+	if err := NewEncoder(&sb, opts...).Encode(t); err != nil {
+		return "", err
+	}
+	return sb.String(), nil
+}
+
+// EncodeToFile encodes a timeline to an SVG file at path, creating or
+// truncating it. If encoding fails the partially written file is removed.
+func EncodeToFile(path string, t *gotio.Timeline, opts ...Option) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", path, err)
+	}
+
+	if err := NewEncoder(f, opts...).Encode(t); err != nil {
+		f.Close()
+		os.Remove(path)
+		return fmt.Errorf("failed to encode %s: %w", path, err)
+	}
+
+	if err := f.Close(); err != nil {
+		os.Remove(path)
+		return fmt.Errorf("failed to close %s: %w", path, err)
+	}
+
+	return nil
+}
+
+// EncodeGzip encodes a timeline to gzip-compressed SVG, the .svgz format
+// that browsers display directly.
+func EncodeGzip(w io.Writer, t *gotio.Timeline, opts ...Option) error {
+	zw := gzip.NewWriter(w)
+	if err := NewEncoder(zw, opts...).Encode(t); err != nil {
+		zw.Close()
+		return err
+	}
+	if err := zw.Close(); err != nil {
+		return fmt.Errorf("failed to close gzip stream: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,115 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Contributors to the OpenTimelineIO project
+
+package svg
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestEncodeToString(t *testing.T) {
+	svg, err := EncodeToString(buildSimpleTimeline(t), WithSize(800, 400))
+	if err != nil {
+		t.Fatalf("EncodeToString failed: %v", err)
+	}
+
+	if !strings.HasPrefix(svg, `<?xml version="1.0" encoding="UTF-8"?>`) {
+		t.Error("SVG missing XML declaration")
+	}
+
+	if !strings.Contains(svg, `width="800"`) {
+		t.Error("Options were not applied")
+	}
+}
+
+func TestEncodeToStringErrors(t *testing.T) {
+	svg, err := EncodeToString(nil)
+	if err == nil || !strings.Contains(err.Error(), "timeline is nil") {
+		t.Errorf("Expected nil timeline error, got %v", err)
+	}
+
+	if svg != "" {
+		t.Error("Expected empty string on error")
+	}
+}
+
+func TestEncodeToFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "timeline.svg")
+
+	if err := EncodeToFile(path, buildSimpleTimeline(t)); err != nil {
+		t.Fatalf("EncodeToFile failed: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read output: %v", err)
+	}
+
+	if !strings.HasSuffix(string(data), "</svg>\n") {
+		t.Error("SVG file is incomplete")
+	}
+}
+
+func TestEncodeToFileRemovesOnError(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "timeline.svg")
+
+	err := EncodeToFile(path, nil)
+	if err == nil {
+		t.Fatal("Expected error for nil timeline")
+	}
+
+	if !strings.Contains(err.Error(), path) {
+		t.Errorf("Error does not mention path: %v", err)
+	}
+
+	if _, statErr := os.Stat(path); !os.IsNotExist(statErr) {
+		t.Error("Partially written file was not removed")
+	}
+}
+
+func TestEncodeGzip(t *testing.T) {
+	var buf bytes.Buffer
+	if err := EncodeGzip(&buf, buildSimpleTimeline(t), WithSize(800, 400)); err != nil {
+		t.Fatalf("EncodeGzip failed: %v", err)
+	}
+
+	zr, err := gzip.NewReader(&buf)
+	if err != nil {
+		t.Fatalf("Output is not gzip: %v", err)
+	}
+	data, err := io.ReadAll(zr)
+	if err != nil {
+		t.Fatalf("Failed to decompress output: %v", err)
+	}
+
+	expected, err := EncodeToString(buildSimpleTimeline(t), WithSize(800, 400))
+	if err != nil {
+		t.Fatalf("EncodeToString failed: %v", err)
+	}
+	if string(data) != expected {
+		t.Error("Decompressed output differs from the uncompressed SVG")
+	}
+}
+
+// failingWriter fails every write.
+type failingWriter struct{}
+
+func (failingWriter) Write(p []byte) (int, error) {
+	return 0, errors.New("disk full")
+}
+
+func TestEncodeGzipCloseError(t *testing.T) {
+	// The SVG is buffered by the compressor, so the failure surfaces when
+	// the stream is closed.
+	err := EncodeGzip(failingWriter{}, buildSimpleTimeline(t))
+	if err == nil || !strings.Contains(err.Error(), "disk full") {
+		t.Errorf("Expected write error, got %v", err)
+	}
+}
@@ -0,0 +1,44 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Contributors to the OpenTimelineIO project
+
+package svg
+
+// trackToggleScript collapses and expands tracks when their label or glyph
+// is clicked, by hiding the group of items in the track. It marks the
+// document interactive, which shows the glyphs, so that without scripts
+// the tracks stay expanded with no glyphs that do nothing. It is written
+// without line comments so that it survives compact output.
+const trackToggleScript = `(function () {
+  var script = document.currentScript;
+  var root = (script && script.closest("svg")) || document.documentElement;
+  root.classList.add("interactive");
+  root.querySelectorAll(".track-toggle").forEach(function (toggle) {
+    toggle.addEventListener("click", function () {
+      var track = toggle.closest(".track");
+      var items = track && track.querySelector(".track-items");
+      if (!items) {
+        return;
+      }
+      var collapsed = items.style.display === "none";
+      items.style.display = collapsed ? "" : "none";
+      track.querySelectorAll(".track-toggle-glyph").forEach(function (glyph) {
+        glyph.textContent = collapsed ? "−" : "+";
+      });
+    });
+  });
+})();`
+
+// trackToggleAttrs returns the attributes marking a track's label or glyph
+// as a button that collapses the track, for assistive technology.
+func trackToggleAttrs(trackName string) []Attr {
+	return []Attr{
+		{"role", "button"},
+		{"aria-label", "Collapse or expand track " + trackName},
+	}
+}
+
+// drawTrackToggle draws the glyph that collapses the named track centered
+// at x, y, showing the track is expanded.
+func drawTrackToggle(builder *SVGBuilder, x, y float64, trackName string) error {
+	return builder.WriteTextWithAttrs(x, y, "−", "middle", "", "track-label track-toggle track-toggle-glyph", "", trackToggleAttrs(trackName))
+}
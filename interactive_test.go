@@ -0,0 +1,71 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Contributors to the OpenTimelineIO project
+
+package svg
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestInteractive(t *testing.T) {
+	timeline := buildColumns(t)
+	plain := encodeString(t, timeline, nil)
+	if strings.Contains(plain, "<script") || strings.Contains(plain, `class="track-items"`) {
+		t.Error("Interactive mode should be off by default")
+	}
+
+	svg := encodeString(t, timeline, func(enc *Encoder) {
+		enc.SetInteractive(true)
+	})
+	if n := strings.Count(svg, `class="track-label track-toggle"`); n != 2 {
+		t.Errorf("Expected 2 clickable track labels, found %d", n)
+	}
+	if n := strings.Count(svg, `class="track-label track-toggle track-toggle-glyph"`); n != 2 {
+		t.Errorf("Expected 2 toggle glyphs, found %d", n)
+	}
+
+	// Each track's items are grouped so the script can hide them
+	track := svg[strings.Index(svg, `<g id="track-V1"`):strings.Index(svg, `<g id="track-A1"`)]
+	items := strings.Index(track, `<g class="track-items">`)
+	if items < 0 || items > strings.Index(track, `id="clip-Shot_1"`) {
+		t.Error("Track items should be grouped after the label")
+	}
+
+	// The script comes last, so it can find every track
+	script := strings.Index(svg, "<script><![CDATA[")
+	if script < 0 || script < strings.LastIndex(svg, `track-toggle-glyph" dominant-baseline`) || !strings.HasSuffix(strings.TrimSpace(svg), "]]></script>\n</svg>") {
+		t.Error("Script not embedded at the end of the document")
+	}
+	if strings.Contains(svg[script:], "http") {
+		t.Error("Script should not load anything")
+	}
+
+	// Without scripts the glyphs are hidden by default
+	if !strings.Contains(svg, ".track-toggle-glyph {\n      display: none;") {
+		t.Error("Glyphs should be hidden unless the script runs")
+	}
+
+	compact := encodeString(t, timeline, func(enc *Encoder) {
+		enc.SetInteractive(true)
+		enc.SetPretty(false)
+	})
+	if !strings.Contains(compact, `<script><![CDATA[(function () { var script`) {
+		t.Error("Script not compacted")
+	}
+}
+
+func TestInteractiveAriaLabels(t *testing.T) {
+	timeline := buildColumns(t)
+	plain := encodeString(t, timeline, nil)
+	if strings.Contains(plain, `role="button"`) {
+		t.Error("Track labels should only be buttons in interactive mode")
+	}
+
+	svg := encodeString(t, timeline, func(enc *Encoder) {
+		enc.SetInteractive(true)
+	})
+	if n := strings.Count(svg, `role="button" aria-label="Collapse or expand track V1"`); n != 2 {
+		t.Errorf("Expected the label and glyph of V1 to be labeled buttons, found %d", n)
+	}
+}
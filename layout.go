@@ -0,0 +1,149 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Contributors to the OpenTimelineIO project
+
+package svg
+
+import (
+	"context"
+	"math"
+
+	"github.com/Avalanche-io/gotio/opentime"
+)
+
+// Rect is a rectangle in SVG pixel coordinates.
+type Rect struct {
+	X      float64 `json:"x"`
+	Y      float64 `json:"y"`
+	Width  float64 `json:"width"`
+	Height float64 `json:"height"`
+}
+
+// layout holds the geometry computed for a single Encode call.
+type layout struct {
+	ctx context.Context // checked for cancellation while drawing
+
+	width, height int  // canvas size
+	marginTop     int  // top margin, expanded to fit the title
+	marginBottom  int  // bottom margin, expanded to fit a scale bar
+	rulerHeight   int  // zero when a scale bar replaces the ruler
+	dualRuler     bool // ruler labels timecode above elapsed seconds
+
+	vertical        bool    // time runs down the canvas, with tracks as columns
+	contentLeft     float64 // start of the time axis: its x, or y if vertical
+	contentWidth    float64 // length of the time axis in pixels
+	timeScale       float64 // pixels per second
+	originX         float64 // time zero on the time axis, before the content when cropped
+	lanesLeft       float64 // x of the first track's left edge, if vertical
+	zoom            float64 // zoom factor of the time axis, 1 when not zoomed
+	durationSeconds float64 // duration shown, the view range's if cropped
+	trackHeight     int
+	numTracks       int
+
+	rate         float64 // frame rate for time labels
+	startSeconds float64 // global start time, added to ruler labels
+	viewStart    float64 // start of the view range, if cropped
+
+	stats Stats // counts of what has been drawn so far
+
+	clipRects map[string]Rect // clip bounds by element id, if recorded
+	doc       *LayoutJSON     // geometry of everything drawn, if recorded
+
+	symbols map[rectStyle]string // symbol ids of shared shapes, if defined
+
+	viewClipID string // id of the clip path of the view range, if cropped
+}
+
+// rectStyle identifies the appearance of a rectangle.
+type rectStyle struct {
+	fill, stroke string
+}
+
+// rulerY returns the y position of the top of the ruler.
+func (l *layout) rulerY() float64 {
+	return float64(l.marginTop)
+}
+
+// tracksTop returns the position of the first track across the time axis:
+// the y of its top, or the x of its left edge if vertical.
+func (l *layout) tracksTop() float64 {
+	if l.vertical {
+		return l.lanesLeft
+	}
+	return float64(l.marginTop + l.rulerHeight)
+}
+
+// tracksBottom returns the position of the far edge of the last track
+// across the time axis.
+func (l *layout) tracksBottom() float64 {
+	return l.tracksTop() + float64(l.numTracks*l.trackHeight)
+}
+
+// minDurationSeconds is the shortest duration the time axis is scaled to,
+// so vanishingly short timelines don't scale it to infinity.
+const minDurationSeconds = 1e-6
+
+// finite reports whether v is neither NaN nor infinite.
+func finite(v float64) bool {
+	return !math.IsNaN(v) && !math.IsInf(v, 0)
+}
+
+// timeOffset returns how far time t is from time zero along the time axis,
+// in pixels. The time is converted to frames at the timeline's rate and to
+// pixels once, so edges stay frame accurate at rates such as 23.976, and
+// items that meet at the same time meet at the same pixel.
+func (l *layout) timeOffset(t opentime.RationalTime) float64 {
+	if l.rate > 0 && t.Rate() > 0 {
+		return t.ValueRescaledTo(l.rate) * (l.timeScale / l.rate)
+	}
+	return t.ToSeconds() * l.timeScale
+}
+
+// itemPadding is the space left between items and the edges of their
+// track.
+const itemPadding = 2.0
+
+// place maps a rectangle from layout coordinates, with time along x and
+// tracks along y, to the canvas. Vertical layouts swap the axes.
+func (l *layout) place(x, y, width, height float64) (float64, float64, float64, float64) {
+	if l.vertical {
+		return y, x, height, width
+	}
+	return x, y, width, height
+}
+
+// placePoint maps a point from layout coordinates to the canvas.
+func (l *layout) placePoint(x, y float64) (float64, float64) {
+	if l.vertical {
+		return y, x
+	}
+	return x, y
+}
+
+// placeItem maps the band an item is drawn in to the canvas. Items inset
+// their y axis by itemPadding, which is the time axis in vertical layouts,
+// so there the band is grown along time and inset across the track
+// instead.
+func (l *layout) placeItem(x, y, width, height float64) (float64, float64, float64, float64) {
+	if l.vertical {
+		return y + itemPadding, x - itemPadding, height - 2*itemPadding, width + 2*itemPadding
+	}
+	return x, y, width, height
+}
+
+// recordItem adds an item drawn in the band at x, y on the canvas to the
+// last track of the layout document, if one is being recorded. Items are
+// inset vertically by the same padding they are drawn with.
+func (l *layout) recordItem(id, kind, name string, rng opentime.TimeRange, x, y, width, height float64) {
+	if l.doc == nil || len(l.doc.Tracks) == 0 {
+		return
+	}
+	track := &l.doc.Tracks[len(l.doc.Tracks)-1]
+	track.Items = append(track.Items, ItemLayout{
+		ID:       id,
+		Type:     kind,
+		Name:     name,
+		Start:    rng.StartTime().ToSeconds(),
+		Duration: rng.Duration().ToSeconds(),
+		Rect:     Rect{X: x, Y: y + itemPadding, Width: width, Height: height - 2*itemPadding},
+	})
+}
@@ -0,0 +1,36 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Contributors to the OpenTimelineIO project
+
+package svg
+
+// LayoutJSON describes the geometry of an encoded timeline in the SVG's
+// pixel coordinates. It is returned by EncodeWithLayout and can be
+// marshaled with encoding/json.
+type LayoutJSON struct {
+	Width    int           `json:"width"`
+	Height   int           `json:"height"`
+	Duration float64       `json:"duration"` // seconds
+	Tracks   []TrackLayout `json:"tracks"`
+}
+
+// TrackLayout describes a track lane.
+type TrackLayout struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	Kind string `json:"kind"`
+	Rect
+	Items []ItemLayout `json:"items"`
+}
+
+// ItemLayout describes a clip, gap, transition, or nested stack drawn in a
+// track. Items of tracks nested in a stack are listed with the lane that
+// contains the stack, after the stack itself.
+type ItemLayout struct {
+	ID   string `json:"id"`
+	Type string `json:"type"` // "clip", "gap", "transition", or "stack"
+	Name string `json:"name"`
+	// Start and Duration are in seconds, relative to the item's track.
+	Start    float64 `json:"start"`
+	Duration float64 `json:"duration"`
+	Rect
+}
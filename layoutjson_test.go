@@ -0,0 +1,79 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Contributors to the OpenTimelineIO project
+
+package svg
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/Avalanche-io/gotio"
+	"github.com/Avalanche-io/gotio/opentime"
+)
+
+func TestEncodeWithLayout(t *testing.T) {
+	timeline := gotio.NewTimeline("", nil, nil)
+	track := gotio.NewTrack("V1", nil, gotio.TrackKindVideo, nil, nil)
+	children := []gotio.Composable{
+		newTestClip("Shot A", 48, 24),
+		gotio.NewGapWithDuration(opentime.NewRationalTime(24, 24)),
+		newTestClip("Shot B", 48, 24),
+	}
+	for _, child := range children {
+		if err := track.AppendChild(child); err != nil {
+			t.Fatalf("Failed to append child: %v", err)
+		}
+	}
+	if err := timeline.Tracks().AppendChild(track); err != nil {
+		t.Fatalf("Failed to append track: %v", err)
+	}
+
+	var buf bytes.Buffer
+	doc, err := NewEncoder(&buf).EncodeWithLayout(timeline)
+	if err != nil {
+		t.Fatalf("EncodeWithLayout failed: %v", err)
+	}
+	svg := buf.String()
+
+	if doc.Width != DefaultWidth || doc.Height != DefaultHeight || doc.Duration != 5 {
+		t.Errorf("Unexpected document size: %dx%d, %vs", doc.Width, doc.Height, doc.Duration)
+	}
+	if len(doc.Tracks) != 1 || doc.Tracks[0].ID != "track-V1" || doc.Tracks[0].Kind != gotio.TrackKindVideo {
+		t.Fatalf("Unexpected tracks: %+v", doc.Tracks)
+	}
+
+	items := doc.Tracks[0].Items
+	if len(items) != 3 {
+		t.Fatalf("Expected 3 items, got %d", len(items))
+	}
+
+	expected := []struct {
+		id, kind string
+		start    float64
+	}{
+		{"clip-Shot_A", "clip", 0},
+		{"gap-V1-1", "gap", 2},
+		{"clip-Shot_B", "clip", 3},
+	}
+	for i, exp := range expected {
+		item := items[i]
+		if item.ID != exp.id || item.Type != exp.kind || item.Start != exp.start {
+			t.Errorf("Item %d = %+v, want %s %s at %v", i, item, exp.kind, exp.id, exp.start)
+		}
+		rect := fmt.Sprintf(`x="%.2f" y="%.2f" width="%.2f" height="%.2f"`, item.X, item.Y, item.Width, item.Height)
+		if !strings.Contains(svg, rect) {
+			t.Errorf("Item %s geometry does not match the SVG: %s", item.ID, rect)
+		}
+	}
+
+	data, err := json.Marshal(doc)
+	if err != nil {
+		t.Fatalf("Failed to marshal layout: %v", err)
+	}
+	if !strings.Contains(string(data), `"id":"clip-Shot_A","type":"clip","name":"Shot A","start":0,"duration":2,"x":`) {
+		t.Errorf("Unexpected JSON: %s", data)
+	}
+}
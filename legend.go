@@ -0,0 +1,82 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Contributors to the OpenTimelineIO project
+
+package svg
+
+import "fmt"
+
+// Legend layout constants.
+const (
+	legendItemWidth = 90
+	legendHeight    = 20
+	legendSwatch    = 14
+	legendPadding   = 4
+	legendItems     = 4
+	legendWidth     = legendItems*legendItemWidth + 2*legendPadding
+
+	// legendMargin is the top margin needed to fit the legend.
+	legendMargin = legendHeight + 2*legendPadding
+)
+
+// legendPosition returns the top-left corner of the legend: right-aligned
+// and centered in the top margin, above the minimap if there is one.
+func (e *Encoder) legendPosition(l *layout) (x, y float64) {
+	top := l.marginTop
+	if e.showMinimap && e.orientation != OrientationVertical {
+		top -= minimapHeight + 2*minimapGap
+	}
+	x = float64(l.width - e.marginRight - legendWidth)
+	y = max(float64(top-legendHeight)/2, 0)
+	return x, y
+}
+
+// drawLegend draws a legend mapping colors and styles to item types,
+// right-aligned in the top margin above the ruler.
+func (e *Encoder) drawLegend(builder *SVGBuilder, l *layout) error {
+	items := []struct {
+		label string
+		class string
+		fill  string
+	}{
+		{"Video clip", "clip", e.theme.VideoTrack},
+		{"Audio clip", "clip", e.theme.AudioTrack},
+		{"Gap", "gap", e.theme.Gap},
+		{"Transition", "transition", ""},
+	}
+
+	x, y := e.legendPosition(l)
+
+	if err := builder.StartGroup(builder.UniqueID("legend"), "legend"); err != nil {
+		return err
+	}
+
+	if err := builder.WriteRect(x, y, legendWidth, legendHeight, e.theme.TrackLabelBg, e.theme.Grid, "", "legend-bg", ""); err != nil {
+		return err
+	}
+
+	swatchY := y + (legendHeight-10)/2
+	for i, item := range items {
+		itemX := x + legendPadding + float64(i*legendItemWidth)
+
+		if item.class == "transition" {
+			path := fmt.Sprintf("M %.2f %.2f L %.2f %.2f", itemX, swatchY+10, itemX+legendSwatch, swatchY)
+			if err := builder.WritePath(path, "none", e.theme.Transition, 3, "transition"); err != nil {
+				return err
+			}
+		} else {
+			stroke := e.theme.ClipBorder
+			if item.class == "gap" {
+				stroke = e.theme.GapBorder
+			}
+			if err := builder.WriteRect(itemX, swatchY, legendSwatch, 10, item.fill, stroke, "", item.class, ""); err != nil {
+				return err
+			}
+		}
+
+		if err := builder.WriteText(itemX+legendSwatch+legendPadding, y+legendHeight/2, item.label, "start", "", "legend-text"); err != nil {
+			return err
+		}
+	}
+
+	return builder.EndGroup()
+}
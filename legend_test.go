@@ -0,0 +1,121 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Contributors to the OpenTimelineIO project
+
+package svg
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/Avalanche-io/gotio"
+)
+
+func TestLegend(t *testing.T) {
+	svg := encodeString(t, buildSimpleTimeline(t), nil)
+	if strings.Contains(svg, `class="legend"`) {
+		t.Error("Legend should be off by default")
+	}
+
+	svg = encodeString(t, buildSimpleTimeline(t), func(enc *Encoder) {
+		enc.SetShowLegend(true)
+	})
+
+	if !strings.Contains(svg, `class="legend"`) {
+		t.Fatal("SVG missing legend")
+	}
+
+	for _, label := range []string{"Video clip", "Audio clip", "Gap", "Transition"} {
+		if !strings.Contains(svg, ">"+label+"</text>") {
+			t.Errorf("Legend missing %q", label)
+		}
+	}
+
+	// The legend sits in the top margin, above the ruler.
+	legend := svg[strings.Index(svg, `class="legend"`):]
+	legend = legend[:strings.Index(legend, "</g>")]
+	if !strings.Contains(legend, `y="20.00" width="368.00" height="20.00"`) {
+		t.Error("Legend not positioned in the top margin")
+	}
+}
+
+func TestLegendClearOfRuler(t *testing.T) {
+	// A zero top margin grows to fit the legend above the ruler
+	var buf bytes.Buffer
+	enc := NewEncoder(&buf, WithMargins(0, MarginRight, MarginBottom, MarginLeft))
+	enc.SetShowLegend(true)
+	enc.SetShowTitle(false)
+	if err := enc.Encode(buildSimpleTimeline(t)); err != nil {
+		t.Fatalf("Encode failed: %v", err)
+	}
+	svg := buf.String()
+	if !strings.Contains(svg, fmt.Sprintf(`y="%d.00" width="%d.00" height="%d.00" fill="%s" stroke="%s" class="legend-bg"`, legendPadding, legendWidth, legendHeight, TrackLabelBg, GridColor)) {
+		t.Error("Legend should sit in a top margin grown to fit it")
+	}
+	if !strings.Contains(svg, fmt.Sprintf(`y="%d.00" width="1060.00" height="%d.00" fill="%s" stroke="%s" class="ruler-bg"`, legendMargin, RulerHeight, TrackLabelBg, GridColor)) {
+		t.Error("Ruler should start below the legend")
+	}
+
+	// With a minimap too, the legend stays above it
+	buf.Reset()
+	enc = NewEncoder(&buf, WithMargins(0, MarginRight, MarginBottom, MarginLeft))
+	enc.SetShowLegend(true)
+	enc.SetShowTitle(false)
+	enc.SetShowMinimap(true)
+	if err := enc.Encode(buildSimpleTimeline(t)); err != nil {
+		t.Fatalf("Encode failed: %v", err)
+	}
+	svg = buf.String()
+	if !strings.Contains(svg, fmt.Sprintf(`y="%d.00" width="%d.00" height="%d.00" fill="%s" stroke="%s" class="legend-bg"`, legendPadding, legendWidth, legendHeight, TrackLabelBg, GridColor)) {
+		t.Error("Legend should sit above the minimap")
+	}
+	if !strings.Contains(svg, fmt.Sprintf(`y="%d.00" width="1060.00" height="%d.00" fill="%s" stroke="%s" class="minimap-bg"`, legendMargin+minimapGap, minimapHeight, TrackLabelBg, GridColor)) {
+		t.Error("Minimap should sit below the legend")
+	}
+}
+
+// buildNamedTimeline returns a timeline with one 10s clip, titled name.
+func buildNamedTimeline(t *testing.T, name string) *gotio.Timeline {
+	t.Helper()
+	timeline := gotio.NewTimeline(name, nil, nil)
+	track := gotio.NewTrack("V1", nil, gotio.TrackKindVideo, nil, nil)
+	if err := track.AppendChild(newTestClip("Shot", 240, 24)); err != nil {
+		t.Fatalf("Failed to append clip: %v", err)
+	}
+	if err := timeline.Tracks().AppendChild(track); err != nil {
+		t.Fatalf("Failed to append track: %v", err)
+	}
+	return timeline
+}
+
+func TestLegendBesideTitle(t *testing.T) {
+	long := strings.Repeat("Very Long Title ", 20)
+	svg := encodeString(t, buildNamedTimeline(t, long), func(enc *Encoder) {
+		enc.SetShowLegend(true)
+	})
+
+	title := regexp.MustCompile(`class="timeline-title" dominant-baseline="middle">([^<]*)</text>`).FindStringSubmatch(svg)
+	if title == nil {
+		t.Fatal("SVG missing title")
+	}
+	legendX := float64(DefaultWidth - MarginRight - legendWidth)
+	if !strings.HasSuffix(title[1], ellipsis) || MarginLeft+estimateTextWidth(title[1], titleFontSize) > legendX {
+		t.Errorf("Title %q should be truncated to end before the legend at %.0f", title[1], legendX)
+	}
+
+	// Titles that fit are kept whole, as they are without a legend
+	for _, legend := range []bool{true, false} {
+		svg = encodeString(t, buildNamedTimeline(t, "Short"), func(enc *Encoder) {
+			enc.SetShowLegend(legend)
+		})
+		if !strings.Contains(svg, `class="timeline-title" dominant-baseline="middle">Short</text>`) {
+			t.Errorf("Short title should be kept whole (legend %v)", legend)
+		}
+	}
+	svg = encodeString(t, buildNamedTimeline(t, long), nil)
+	if !strings.Contains(svg, ">"+long+"</text>") {
+		t.Error("Title should only be truncated beside the legend")
+	}
+}
@@ -0,0 +1,110 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Contributors to the OpenTimelineIO project
+
+package svg
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/Avalanche-io/gotio"
+)
+
+// markerColors maps OTIO marker color names to hex colors.
+var markerColors = map[string]string{
+	"PINK":    "#FF69B4",
+	"RED":     "#E53935",
+	"ORANGE":  "#FB8C00",
+	"YELLOW":  "#FDD835",
+	"GREEN":   "#43A047",
+	"CYAN":    "#00ACC1",
+	"BLUE":    "#1E88E5",
+	"PURPLE":  "#8E24AA",
+	"MAGENTA": "#D81B60",
+	"BLACK":   "#000000",
+	"WHITE":   "#FFFFFF",
+}
+
+// markerColor returns the hex color for an OTIO marker color name,
+// defaulting to red for unknown names.
+func markerColor(name string) string {
+	if c, ok := markerColors[strings.ToUpper(name)]; ok {
+		return c
+	}
+	return markerColors["RED"]
+}
+
+// markerTitle returns the tooltip text for a marker.
+func markerTitle(marker *gotio.Marker) string {
+	if marker.Name() == "" {
+		return "Marker"
+	}
+	return marker.Name()
+}
+
+// drawClipMarkers draws a clip's markers as small flags along the top edge
+// of the clip. Marker times are in the clip's source time, so they are
+// offset by the start of the source range.
+func (e *Encoder) drawClipMarkers(builder *SVGBuilder, clip *gotio.Clip, x, y, width, timeScale float64) error {
+	sourceStart := 0.0
+	if sr := clip.SourceRange(); sr != nil {
+		sourceStart = sr.StartTime().ToSeconds()
+	}
+
+	for _, marker := range clip.Markers() {
+		offset := (marker.MarkedRange().StartTime().ToSeconds() - sourceStart) * timeScale
+		if offset < 0 || offset > width {
+			continue
+		}
+
+		mx := x + offset
+		flag := fmt.Sprintf("M %.2f %.2f L %.2f %.2f L %.2f %.2f Z", mx-4, y, mx+4, y, mx, y+7)
+		if err := builder.WritePathWithTitle(flag, markerColor(string(marker.Color())), e.theme.ClipBorder, 1, "", "marker", markerTitle(marker)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// drawTimelineMarkers draws markers on the timeline's top-level stack along
+// the bottom of the ruler. Point markers are drawn as ticks and markers with
+// a duration as spans.
+func (e *Encoder) drawTimelineMarkers(builder *SVGBuilder, l *layout, markers []*gotio.Marker) error {
+	if err := builder.StartGroup(builder.UniqueID("timeline-markers"), "timeline-markers"); err != nil {
+		return err
+	}
+
+	left := float64(e.marginLeft)
+	bottom := l.tracksTop()
+	for _, marker := range markers {
+		rng := marker.MarkedRange()
+		start := l.originX - left + l.timeOffset(rng.StartTime())
+		color := markerColor(string(marker.Color()))
+		title := markerTitle(marker)
+		span := l.originX - left + l.timeOffset(rng.EndTimeExclusive()) - start
+		if span > 0 {
+			end := math.Min(start+span, l.contentWidth)
+			start = math.Max(start, 0)
+			if end <= start {
+				continue
+			}
+			if err := builder.WriteRectWithTitle(left+start, bottom-6, end-start, 6, color, "", "", "timeline-marker", title); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if start < 0 || start > l.contentWidth {
+			continue
+		}
+
+		tick := fmt.Sprintf("M %.2f %.2f L %.2f %.2f", left+start, bottom-10, left+start, bottom)
+		if err := builder.WritePathWithTitle(tick, "none", color, 2, "", "timeline-marker", title); err != nil {
+			return err
+		}
+	}
+
+	return builder.EndGroup()
+}
@@ -0,0 +1,116 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Contributors to the OpenTimelineIO project
+
+package svg
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/Avalanche-io/gotio"
+	"github.com/Avalanche-io/gotio/opentime"
+)
+
+func TestMarkerColor(t *testing.T) {
+	tests := []struct {
+		name     string
+		expected string
+	}{
+		{"RED", "#E53935"},
+		{"green", "#43A047"},
+		{"CYAN", "#00ACC1"},
+		{"UNKNOWN", "#E53935"},
+		{"", "#E53935"},
+	}
+
+	for _, tt := range tests {
+		result := markerColor(tt.name)
+		if result != tt.expected {
+			t.Errorf("markerColor(%q) = %s, want %s", tt.name, result, tt.expected)
+		}
+	}
+}
+
+func TestClipMarkers(t *testing.T) {
+	marker := gotio.NewMarker(
+		"Fix color",
+		opentime.NewTimeRange(opentime.NewRationalTime(48, 24), opentime.NewRationalTime(0, 24)),
+		gotio.MarkerColorGreen,
+		"",
+		nil,
+	)
+
+	// Source range starts at 1 second, so the marker is 1 second in.
+	sr := opentime.NewTimeRange(opentime.NewRationalTime(24, 24), opentime.NewRationalTime(240, 24))
+	clip := gotio.NewClip("Shot", nil, &sr, nil, nil, []*gotio.Marker{marker}, "", nil)
+
+	timeline := gotio.NewTimeline("Markers", nil, nil)
+	track := gotio.NewTrack("V1", nil, gotio.TrackKindVideo, nil, nil)
+	if err := track.AppendChild(clip); err != nil {
+		t.Fatalf("Failed to append clip: %v", err)
+	}
+	if err := timeline.Tracks().AppendChild(track); err != nil {
+		t.Fatalf("Failed to append track: %v", err)
+	}
+
+	svg := encodeString(t, timeline, func(enc *Encoder) {
+		enc.SetPixelsPerSecond(100)
+	})
+
+	if !strings.Contains(svg, `class="marker"`) {
+		t.Fatal("SVG missing clip marker")
+	}
+
+	if !strings.Contains(svg, `fill="#43A047"`) {
+		t.Error("Marker not drawn in its color")
+	}
+
+	if !strings.Contains(svg, "<title>Fix color</title>") {
+		t.Error("Marker missing name tooltip")
+	}
+
+	mx := float64(MarginLeft + 100)
+	if !strings.Contains(svg, fmt.Sprintf(`d="M %.2f`, mx-4)) {
+		t.Errorf("Marker not positioned at x=%.2f", mx)
+	}
+}
+
+func TestTimelineMarkers(t *testing.T) {
+	timeline := buildSimpleTimeline(t)
+
+	svg := encodeString(t, timeline, nil)
+	if strings.Contains(svg, `class="timeline-markers"`) {
+		t.Error("Timeline without markers should not have a marker group")
+	}
+
+	timeline.Tracks().SetMarkers([]*gotio.Marker{
+		gotio.NewMarker("Chapter 1",
+			opentime.NewTimeRange(opentime.NewRationalTime(24, 24), opentime.NewRationalTime(0, 24)),
+			gotio.MarkerColorBlue, "", nil),
+		gotio.NewMarker("Act 2",
+			opentime.NewTimeRange(opentime.NewRationalTime(120, 24), opentime.NewRationalTime(48, 24)),
+			gotio.MarkerColorYellow, "", nil),
+	})
+
+	svg = encodeString(t, timeline, func(enc *Encoder) {
+		enc.SetPixelsPerSecond(100)
+	})
+
+	if strings.Count(svg, `class="timeline-marker"`) != 2 {
+		t.Fatalf("Expected 2 timeline markers, found %d", strings.Count(svg, `class="timeline-marker"`))
+	}
+
+	// Point marker at 1s is a tick; the 2 second range at 5s is a span.
+	if !strings.Contains(svg, fmt.Sprintf(`d="M %d.00`, MarginLeft+100)) {
+		t.Error("Point marker not drawn as a tick at its start time")
+	}
+
+	if !strings.Contains(svg, fmt.Sprintf(`x="%d.00" y="%d.00" width="200.00" height="6.00" fill="#FDD835"`, MarginLeft+500, MarginTop+RulerHeight-6)) {
+		t.Error("Range marker not drawn as a span")
+	}
+
+	if !strings.Contains(svg, "<title>Chapter 1</title>") {
+		t.Error("Timeline marker missing name tooltip")
+	}
+}
@@ -0,0 +1,74 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Contributors to the OpenTimelineIO project
+
+package svg
+
+import (
+	"fmt"
+
+	"github.com/Avalanche-io/gotio"
+)
+
+// mediaIconSize is the width and height of a media type icon.
+const mediaIconSize = 10.0
+
+// mediaIcon is the glyph drawn in the corner of a clip to show the type of
+// its media.
+type mediaIcon int
+
+const (
+	// iconNone draws nothing, for clips without usable media.
+	iconNone mediaIcon = iota
+	// iconVideo is a film frame.
+	iconVideo
+	// iconAudio is a speaker.
+	iconAudio
+	// iconImageSequence is a pair of stacked frames.
+	iconImageSequence
+	// iconGenerator is a four-pointed star.
+	iconGenerator
+)
+
+// mediaIconFor returns the icon for a clip's media reference. External
+// references don't say what kind of media they hold, so the kind of the
+// clip's track decides between video and audio.
+func mediaIconFor(clip *gotio.Clip, trackKind string) mediaIcon {
+	switch clip.MediaReference().(type) {
+	case *gotio.ImageSequenceReference:
+		return iconImageSequence
+	case *gotio.GeneratorReference:
+		return iconGenerator
+	case *gotio.ExternalReference:
+		if trackKind == gotio.TrackKindAudio {
+			return iconAudio
+		}
+		return iconVideo
+	default:
+		return iconNone
+	}
+}
+
+// mediaIconPath returns the SVG path data for an icon whose top-left corner
+// is at x, y.
+func mediaIconPath(icon mediaIcon, x, y float64) string {
+	s := mediaIconSize
+	switch icon {
+	case iconVideo:
+		return fmt.Sprintf("M %.2f %.2f h %.2f v %.2f h %.2f Z M %.2f %.2f v %.2f M %.2f %.2f v %.2f",
+			x, y, s, s, -s, x+s*0.25, y, s, x+s*0.75, y, s)
+	case iconAudio:
+		return fmt.Sprintf("M %.2f %.2f h %.2f l %.2f %.2f v %.2f l %.2f %.2f h %.2f Z M %.2f %.2f q %.2f %.2f 0 %.2f",
+			x, y+s*0.3, s*0.3, s*0.3, -s*0.3, s, -s*0.3, -s*0.3, -s*0.3,
+			x+s*0.8, y+s*0.3, s*0.2, s*0.2, s*0.4)
+	case iconImageSequence:
+		return fmt.Sprintf("M %.2f %.2f h %.2f v %.2f M %.2f %.2f h %.2f v %.2f h %.2f Z",
+			x+s*0.3, y, s*0.7, s*0.7, x, y+s*0.3, s*0.7, s*0.7, -s*0.7)
+	case iconGenerator:
+		cx, cy := x+s/2, y+s/2
+		return fmt.Sprintf("M %.2f %.2f L %.2f %.2f L %.2f %.2f L %.2f %.2f L %.2f %.2f L %.2f %.2f L %.2f %.2f L %.2f %.2f Z",
+			cx, y, cx+s*0.15, cy-s*0.15, x+s, cy, cx+s*0.15, cy+s*0.15,
+			cx, y+s, cx-s*0.15, cy+s*0.15, x, cy, cx-s*0.15, cy-s*0.15)
+	default:
+		return ""
+	}
+}
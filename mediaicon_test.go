@@ -0,0 +1,66 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Contributors to the OpenTimelineIO project
+
+package svg
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/Avalanche-io/gotio"
+	"github.com/Avalanche-io/gotio/opentime"
+)
+
+func TestMediaIconFor(t *testing.T) {
+	tests := []struct {
+		name      string
+		ref       gotio.MediaReference
+		trackKind string
+		expected  mediaIcon
+	}{
+		{"video", gotio.NewExternalReference("", "file:///a.mov", nil, nil), gotio.TrackKindVideo, iconVideo},
+		{"audio", gotio.NewExternalReference("", "file:///a.wav", nil, nil), gotio.TrackKindAudio, iconAudio},
+		{"image sequence", &gotio.ImageSequenceReference{}, gotio.TrackKindVideo, iconImageSequence},
+		{"generator", gotio.NewGeneratorReference("", "SolidColor", nil, nil, nil), gotio.TrackKindVideo, iconGenerator},
+		{"missing", gotio.NewMissingReference("", nil, nil), gotio.TrackKindVideo, iconNone},
+		{"unset", nil, gotio.TrackKindVideo, iconNone},
+	}
+
+	for _, tt := range tests {
+		clip := gotio.NewClip("Clip", tt.ref, nil, nil, nil, nil, "", nil)
+		if icon := mediaIconFor(clip, tt.trackKind); icon != tt.expected {
+			t.Errorf("mediaIconFor(%s) = %d, want %d", tt.name, icon, tt.expected)
+		}
+	}
+}
+
+func TestMediaIconDrawn(t *testing.T) {
+	buildTimeline := func(frames float64) *gotio.Timeline {
+		sr := opentime.NewTimeRange(opentime.NewRationalTime(0, 24), opentime.NewRationalTime(frames, 24))
+		ref := gotio.NewExternalReference("", "file:///a.mov", nil, nil)
+
+		timeline := gotio.NewTimeline("Icons", nil, nil)
+		track := gotio.NewTrack("V1", nil, gotio.TrackKindVideo, nil, nil)
+		if err := track.AppendChild(gotio.NewClip("Shot", ref, &sr, nil, nil, nil, "", nil)); err != nil {
+			t.Fatalf("Failed to append clip: %v", err)
+		}
+		if err := track.AppendChild(newTestClip("Filler", 240, 24)); err != nil {
+			t.Fatalf("Failed to append clip: %v", err)
+		}
+		if err := timeline.Tracks().AppendChild(track); err != nil {
+			t.Fatalf("Failed to append track: %v", err)
+		}
+		return timeline
+	}
+
+	svg := encodeString(t, buildTimeline(240), nil)
+	if n := strings.Count(svg, `class="media-icon"`); n != 1 {
+		t.Errorf("Expected 1 media icon, found %d", n)
+	}
+
+	// Too narrow to fit the icon next to the label
+	svg = encodeString(t, buildTimeline(6), nil)
+	if strings.Contains(svg, `class="media-icon"`) {
+		t.Error("Media icon drawn on a clip too narrow to fit it")
+	}
+}
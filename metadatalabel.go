@@ -0,0 +1,72 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Contributors to the OpenTimelineIO project
+
+package svg
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/Avalanche-io/gotio"
+)
+
+// metadataLookup returns the value at a dotted key path in metadata, such
+// as "review.status", descending through nested dictionaries. A key
+// containing the whole path, dots and all, takes precedence.
+func metadataLookup(metadata map[string]any, path string) (any, bool) {
+	if path == "" {
+		return nil, false
+	}
+	if value, ok := metadata[path]; ok {
+		return value, true
+	}
+
+	var value any = metadata
+	for _, key := range strings.Split(path, ".") {
+		var dict map[string]any
+		switch v := value.(type) {
+		case gotio.AnyDictionary:
+			dict = v
+		case map[string]any:
+			dict = v
+		default:
+			return nil, false
+		}
+		var ok bool
+		if value, ok = dict[key]; !ok {
+			return nil, false
+		}
+	}
+	return value, true
+}
+
+// metadataText formats a metadata value as label text: strings as they are,
+// and numbers and booleans in their usual form. Other values, such as
+// dictionaries and lists, and empty strings have no text.
+func metadataText(value any) (string, bool) {
+	switch v := value.(type) {
+	case string:
+		return v, v != ""
+	case bool:
+		return strconv.FormatBool(v), true
+	case int:
+		return strconv.Itoa(v), true
+	case int64:
+		return strconv.FormatInt(v, 10), true
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), true
+	default:
+		return "", false
+	}
+}
+
+// clipMetadataLabel returns the text of the clip metadata value shown below
+// a clip's name, or "" if none is set or the clip doesn't have one.
+func (e *Encoder) clipMetadataLabel(clip *gotio.Clip) string {
+	value, ok := metadataLookup(clip.Metadata(), e.clipLabelKey)
+	if !ok {
+		return ""
+	}
+	text, _ := metadataText(value)
+	return text
+}
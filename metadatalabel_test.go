@@ -0,0 +1,91 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Contributors to the OpenTimelineIO project
+
+package svg
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/Avalanche-io/gotio"
+	"github.com/Avalanche-io/gotio/opentime"
+)
+
+func TestMetadataLookup(t *testing.T) {
+	md := gotio.AnyDictionary{
+		"scene":       "12A",
+		"take":        3,
+		"approved":    true,
+		"ratio":       2.39,
+		"com.studio":  "flat key",
+		"review":      gotio.AnyDictionary{"status": "final", "notes": []any{"a"}},
+		"vendor":      map[string]any{"shot": map[string]any{"code": "SH010"}},
+		"empty":       "",
+		"com.nested":  nil,
+		"unsupported": []any{"x"},
+	}
+
+	tests := []struct {
+		path     string
+		expected string
+		ok       bool
+	}{
+		{"scene", "12A", true},
+		{"take", "3", true},
+		{"approved", "true", true},
+		{"ratio", "2.39", true},
+		{"com.studio", "flat key", true},
+		{"review.status", "final", true},
+		{"vendor.shot.code", "SH010", true},
+		{"review", "", false},
+		{"review.notes", "", false},
+		{"review.missing", "", false},
+		{"scene.deeper", "", false},
+		{"empty", "", false},
+		{"unsupported", "", false},
+		{"missing", "", false},
+		{"", "", false},
+	}
+
+	for _, tt := range tests {
+		text, ok := "", false
+		if value, found := metadataLookup(md, tt.path); found {
+			text, ok = metadataText(value)
+		}
+		if text != tt.expected || ok != tt.ok {
+			t.Errorf("lookup %q = %q, %v, want %q, %v", tt.path, text, ok, tt.expected, tt.ok)
+		}
+	}
+}
+
+func TestClipMetadataLabel(t *testing.T) {
+	sr := opentime.NewTimeRange(opentime.NewRationalTime(0, 24), opentime.NewRationalTime(120, 24))
+	timeline := gotio.NewTimeline("Dailies", nil, nil)
+	track := gotio.NewTrack("V1", nil, gotio.TrackKindVideo, nil, nil)
+	for _, clip := range []*gotio.Clip{
+		gotio.NewClip("Shot 1", nil, &sr, gotio.AnyDictionary{"shot": gotio.AnyDictionary{"scene": "12A"}}, nil, nil, "", nil),
+		gotio.NewClip("Shot 2", nil, &sr, gotio.AnyDictionary{"shot": gotio.AnyDictionary{"take": 4}}, nil, nil, "", nil),
+	} {
+		if err := track.AppendChild(clip); err != nil {
+			t.Fatalf("Failed to append clip: %v", err)
+		}
+	}
+	if err := timeline.Tracks().AppendChild(track); err != nil {
+		t.Fatalf("Failed to append track: %v", err)
+	}
+
+	if svg := encodeString(t, timeline, nil); strings.Contains(svg, `class="clip-duration clip-metadata"`) {
+		t.Error("Metadata label drawn by default")
+	}
+
+	// Only the clip with a value at the path is labeled
+	svg := encodeString(t, timeline, func(enc *Encoder) {
+		enc.SetClipMetadataLabel("shot.scene")
+	})
+	if n := strings.Count(svg, `class="clip-duration clip-metadata"`); n != 1 {
+		t.Errorf("Expected 1 metadata label, found %d", n)
+	}
+	if !strings.Contains(svg, ">12A</text>") {
+		t.Error("SVG missing metadata label 12A")
+	}
+}
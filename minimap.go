@@ -0,0 +1,99 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Contributors to the OpenTimelineIO project
+
+package svg
+
+import (
+	"math"
+
+	"github.com/Avalanche-io/gotio"
+	"github.com/Avalanche-io/gotio/opentime"
+)
+
+const (
+	// minimapHeight is the height of the minimap strip.
+	minimapHeight = 8
+	// minimapGap is the space kept above and below the minimap.
+	minimapGap = 4
+	// minimapMinWidth is the narrowest a clip is drawn in the minimap.
+	minimapMinWidth = 1.0
+)
+
+// minimapMargin returns the top margin needed to fit the minimap below the
+// title and the legend, if there are any.
+func minimapMargin(title string, legend bool) int {
+	header := 0
+	if title != "" {
+		header = TitleHeight
+	}
+	if legend {
+		header = max(header, legendMargin)
+	}
+	return header + minimapHeight + 2*minimapGap
+}
+
+// drawMinimap draws a strip just above the ruler showing the clips of every
+// track collapsed into one row. It always spans the whole timeline at the
+// canvas width set, however the tracks below are zoomed or cropped, with
+// the part in view outlined if cropped.
+func (e *Encoder) drawMinimap(builder *SVGBuilder, l *layout, lanes []*gotio.Track) error {
+	left := float64(e.marginLeft)
+	width := float64(e.width - e.marginLeft - e.marginRight)
+	y := l.rulerY() - minimapGap - minimapHeight
+	duration := l.stats.Duration.ToSeconds()
+	if width <= 0 || duration <= 0 {
+		return nil
+	}
+	scale := width / duration
+
+	if err := builder.StartGroup(builder.UniqueID("minimap"), "minimap"); err != nil {
+		return err
+	}
+	if err := builder.WriteRect(left, y, width, minimapHeight, e.theme.TrackLabelBg, e.theme.Grid, "", "minimap-bg", ""); err != nil {
+		return err
+	}
+
+	for _, track := range lanes {
+		if err := e.drawOverviewItems(builder, track, left, y, minimapHeight, scale, e.trackColor(track), "minimap-clip"); err != nil {
+			return err
+		}
+	}
+
+	if e.viewRange != nil {
+		x1 := left + e.viewRange.StartTime().ToSeconds()*scale
+		x2 := left + e.viewRange.EndTimeExclusive().ToSeconds()*scale
+		x1, x2 = math.Max(x1, left), math.Min(x2, left+width)
+		if x2 > x1 {
+			if err := builder.WriteRect(x1, y, x2-x1, minimapHeight, "none", e.theme.Playhead, "", "minimap-view", ""); err != nil {
+				return err
+			}
+		}
+	}
+
+	return builder.EndGroup()
+}
+
+// drawOverviewItems draws the clips of a track, and nested stacks as a
+// whole, into a strip of an overview such as the minimap at y, with the
+// timeline starting at left.
+func (e *Encoder) drawOverviewItems(builder *SVGBuilder, track *gotio.Track, left, y, height, scale float64, fill, class string) error {
+	var cursor opentime.RationalTime
+	for i, child := range track.Children() {
+		rng, ok := childRange(track, i, cursor)
+		if !ok {
+			continue
+		}
+		switch child.(type) {
+		case *gotio.Transition:
+			continue
+		case *gotio.Clip, *gotio.Stack:
+			x := left + rng.StartTime().ToSeconds()*scale
+			width := math.Max(rng.Duration().ToSeconds()*scale, minimapMinWidth)
+			if err := builder.WriteRect(x, y, width, height, fill, "", "", class, ""); err != nil {
+				return err
+			}
+		}
+		cursor = rng.EndTimeExclusive()
+	}
+	return nil
+}
@@ -0,0 +1,81 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Contributors to the OpenTimelineIO project
+
+package svg
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/Avalanche-io/gotio"
+	"github.com/Avalanche-io/gotio/opentime"
+)
+
+func TestMinimap(t *testing.T) {
+	timeline := gotio.NewTimeline("Overview", nil, nil)
+	video := gotio.NewTrack("V1", nil, gotio.TrackKindVideo, nil, nil)
+	audio := gotio.NewTrack("A1", nil, gotio.TrackKindAudio, nil, nil)
+	for _, item := range []gotio.Composable{
+		newTestClip("Shot 1", 24, 24),
+		gotio.NewGapWithDuration(opentime.NewRationalTime(24, 24)),
+		newTestClip("Shot 2", 48, 24),
+	} {
+		if err := video.AppendChild(item); err != nil {
+			t.Fatalf("Failed to append item: %v", err)
+		}
+	}
+	if err := audio.AppendChild(newTestClip("Music", 96, 24)); err != nil {
+		t.Fatalf("Failed to append clip: %v", err)
+	}
+	for _, track := range []*gotio.Track{video, audio} {
+		if err := timeline.Tracks().AppendChild(track); err != nil {
+			t.Fatalf("Failed to append track: %v", err)
+		}
+	}
+
+	if strings.Contains(encodeString(t, timeline, nil), `class="minimap"`) {
+		t.Error("Minimap should be off by default")
+	}
+
+	svg := encodeString(t, timeline, func(enc *Encoder) {
+		enc.SetShowMinimap(true)
+	})
+	if n := strings.Count(svg, `class="minimap-clip"`); n != 3 {
+		t.Errorf("Expected 3 clips in the minimap, found %d", n)
+	}
+
+	// The minimap spans the content width just above the ruler
+	y := MarginTop - minimapGap - minimapHeight
+	scale := float64(DefaultWidth-MarginLeft-MarginRight) / 4
+	want := fmt.Sprintf(`<rect x="%.2f" y="%d.00" width="%.2f" height="%d.00" fill="%s" class="minimap-clip" />`, MarginLeft+2*scale, y, 2*scale, minimapHeight, VideoTrackColor)
+	if !strings.Contains(svg, want) {
+		t.Errorf("Minimap clip not placed, want %s", want)
+	}
+
+	// Zooming the tracks leaves the minimap alone, with the view outlined
+	zoomed := encodeString(t, timeline, func(enc *Encoder) {
+		enc.SetShowMinimap(true)
+		enc.SetZoom(3)
+		enc.SetViewRange(opentime.NewTimeRange(opentime.NewRationalTime(24, 24), opentime.NewRationalTime(24, 24)))
+	})
+	if !strings.Contains(zoomed, want) {
+		t.Error("Minimap should not be zoomed or cropped")
+	}
+	if !strings.Contains(zoomed, fmt.Sprintf(`<rect x="%.2f" y="%d.00" width="%.2f" height="%d.00" fill="none" stroke="%s" class="minimap-view" />`, MarginLeft+scale, y, scale, minimapHeight, PlayheadColor)) {
+		t.Error("Minimap should outline the view range")
+	}
+
+	// The top margin grows to fit the minimap below the title
+	var buf bytes.Buffer
+	enc := NewEncoder(&buf, WithMargins(10, MarginRight, MarginBottom, MarginLeft))
+	enc.SetShowMinimap(true)
+	if err := enc.Encode(timeline); err != nil {
+		t.Fatalf("Encode failed: %v", err)
+	}
+	svg = buf.String()
+	if !strings.Contains(svg, fmt.Sprintf(`y="%d.00" width="%d.00" height="%d.00" fill="#F5F5F5" stroke="#CCCCCC" class="minimap-bg"`, TitleHeight+minimapGap, DefaultWidth-MarginLeft-MarginRight, minimapHeight)) {
+		t.Error("Top margin should grow to fit the minimap")
+	}
+}
@@ -0,0 +1,80 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Contributors to the OpenTimelineIO project
+
+package svg
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/Avalanche-io/gotio"
+)
+
+// buildCut returns a timeline with one video track of clips with the given
+// durations in frames at 24 fps.
+func buildCut(t *testing.T, name string, frames ...int) *gotio.Timeline {
+	t.Helper()
+
+	timeline := gotio.NewTimeline(name, nil, nil)
+	track := gotio.NewTrack("V1", nil, gotio.TrackKindVideo, nil, nil)
+	for i, n := range frames {
+		if err := track.AppendChild(newTestClip(fmt.Sprintf("Shot %d", i+1), float64(n), 24)); err != nil {
+			t.Fatalf("Failed to append clip: %v", err)
+		}
+	}
+	if err := timeline.Tracks().AppendChild(track); err != nil {
+		t.Fatalf("Failed to append track: %v", err)
+	}
+	return timeline
+}
+
+func TestEncodeMultiple(t *testing.T) {
+	cuts := []*gotio.Timeline{
+		buildCut(t, "Cut A", 48, 48),
+		buildCut(t, "", 48, 144),
+	}
+
+	var buf bytes.Buffer
+	if err := EncodeMultiple(&buf, cuts, WithSize(1000, 400)); err != nil {
+		t.Fatalf("EncodeMultiple failed: %v", err)
+	}
+	svg := buf.String()
+
+	if err := xml.Unmarshal(buf.Bytes(), new(struct{})); err != nil {
+		t.Errorf("Output is not well-formed XML: %v", err)
+	}
+	if strings.Count(svg, "<?xml") != 1 {
+		t.Error("Output should have a single XML declaration")
+	}
+	if strings.Count(svg, `class="timeline-block"`) != 2 {
+		t.Error("Expected 2 timeline blocks")
+	}
+	if strings.Count(svg, `class="block-separator"`) != 1 {
+		t.Error("Expected 1 separator between blocks")
+	}
+	if !strings.Contains(svg, ">Cut A</text>") || !strings.Contains(svg, ">Timeline 2</text>") {
+		t.Error("Each block should be titled, unnamed timelines by position")
+	}
+
+	// Both cuts start with a 2 second clip, which should be equally wide
+	// on the scale of the longer, 8 second cut
+	width := float64(1000-MarginLeft-MarginRight) / 8 * 2
+	want := fmt.Sprintf(`width="%.2f"`, width)
+	for _, id := range []string{`id="t1-clip-Shot_1"`, `id="t2-clip-Shot_1"`} {
+		i := strings.Index(svg, id)
+		if i < 0 {
+			t.Fatalf("SVG missing %s", id)
+		}
+		start := strings.LastIndex(svg[:i], "<rect ")
+		if !strings.Contains(svg[start:i], want) {
+			t.Errorf("Expected %s to have %s, got %s", id, want, svg[start:i])
+		}
+	}
+
+	if err := EncodeMultiple(&buf, nil); err == nil {
+		t.Error("Expected error for no timelines")
+	}
+}
@@ -0,0 +1,100 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Contributors to the OpenTimelineIO project
+
+package svg
+
+import (
+	"math"
+	"strconv"
+)
+
+const (
+	// minBeatSpacing is the closest beats are marked with minor ticks and
+	// grid lines, in pixels; closer beats leave only the bars marked.
+	minBeatSpacing = 6.0
+	// maxBPM is the fastest tempo accepted for a musical grid.
+	maxBPM = 1000.0
+	// maxBeatsPerBar is the most beats per bar accepted for a musical grid.
+	maxBeatsPerBar = 64
+	// maxIntervalBars is the most bars between major ticks, which bounds
+	// the interval search for timelines far longer than their bars.
+	maxIntervalBars = 1 << 20
+)
+
+// musicalGrid reports whether the ruler and grid follow bars and beats
+// rather than seconds.
+func (e *Encoder) musicalGrid() bool {
+	return e.bpm > 0
+}
+
+// barGrid reports whether ruler ticks fall on bars: the musical grid is set
+// and some power of two bars gives no more than the target number of marks.
+func (e *Encoder) barGrid(l *layout) bool {
+	_, ok := e.barInterval(l)
+	return ok
+}
+
+// barSeconds returns the length of a bar in seconds.
+func (e *Encoder) barSeconds() float64 {
+	return 60 / e.bpm * float64(e.beatsPerBar)
+}
+
+// barInterval returns the time between major ticks of a musical grid in
+// seconds: the smallest power of two bars giving no more than the target
+// number of marks, up to maxIntervalBars. It returns false without a
+// musical grid, or if even that many bars give too many marks, leaving the
+// ruler in seconds.
+func (e *Encoder) barInterval(l *layout) (float64, bool) {
+	if !e.musicalGrid() {
+		return 0, false
+	}
+	span := l.durationSeconds
+	if l.zoom > 0 {
+		span /= l.zoom
+	}
+	marks := e.rulerTargetMarks(l)
+	bars := 1
+	for bars < maxIntervalBars && span/(e.barSeconds()*float64(bars)) > marks {
+		bars *= 2
+	}
+	interval := e.barSeconds() * float64(bars)
+	if !(interval > 0) || span/interval > marks {
+		return 0, false
+	}
+	return interval, true
+}
+
+// beatDivisions returns how many parts each major interval of a bar grid
+// is divided into: beats if they're far enough apart, otherwise bars if
+// they are, and otherwise none.
+func (e *Encoder) beatDivisions(l *layout) int {
+	bars := int(math.Round(e.rulerInterval(l) / e.barSeconds()))
+	if bars < 1 || bars > maxIntervalBars {
+		return 1
+	}
+	if 60/e.bpm*l.timeScale >= minBeatSpacing {
+		return bars * e.beatsPerBar
+	}
+	if e.barSeconds()*l.timeScale >= minBeatSpacing {
+		return bars
+	}
+	return 1
+}
+
+// rulerLabel labels the main row of the ruler tick at the given time in
+// seconds from the start of the ruler: with the bar number on a bar grid,
+// unless a time label function is set, and otherwise with tickLabel in the
+// time format.
+func (e *Encoder) rulerLabel(l *layout, time float64) string {
+	if e.timeLabelFunc == nil && e.barGrid(l) {
+		return e.barLabel(l, time)
+	}
+	return e.tickLabel(l, time, e.timeFormat)
+}
+
+// barLabel labels the ruler tick at the given time in seconds from the
+// start of the ruler with the number of the bar starting there, counting
+// from 1 at the start of the timeline.
+func (e *Encoder) barLabel(l *layout, time float64) string {
+	return strconv.Itoa(int(math.Round((l.viewStart+time)/e.barSeconds())) + 1)
+}
@@ -0,0 +1,266 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Contributors to the OpenTimelineIO project
+
+package svg
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/Avalanche-io/gotio"
+	"github.com/Avalanche-io/gotio/opentime"
+)
+
+func TestSetMusicalGrid(t *testing.T) {
+	enc := NewEncoder(&strings.Builder{})
+	for _, tt := range []struct {
+		bpm         float64
+		beatsPerBar int
+	}{
+		{-120, 4},
+		{120, 0},
+		{math.NaN(), 4},
+		{math.Inf(1), 4},
+		{1e22, 4},
+		{maxBPM + 1, 4},
+		{120, maxBeatsPerBar + 1},
+	} {
+		if err := enc.SetMusicalGrid(tt.bpm, tt.beatsPerBar); err == nil {
+			t.Errorf("SetMusicalGrid(%v, %d) should fail", tt.bpm, tt.beatsPerBar)
+		}
+	}
+	if err := enc.SetMusicalGrid(maxBPM, maxBeatsPerBar); err != nil {
+		t.Errorf("SetMusicalGrid(%v, %d) failed: %v", maxBPM, maxBeatsPerBar, err)
+	}
+	if err := enc.SetMusicalGrid(120, 4); err != nil || !enc.musicalGrid() {
+		t.Fatalf("SetMusicalGrid(120, 4) failed: %v", err)
+	}
+	if err := enc.SetMusicalGrid(0, 0); err != nil || enc.musicalGrid() {
+		t.Errorf("SetMusicalGrid(0, 0) should turn the grid off: %v", err)
+	}
+}
+
+func TestMusicalGrid(t *testing.T) {
+	// At 120 bpm in 4/4 a bar lasts 2s, so the 10s timeline has 5 bars
+	svg := encodeString(t, buildSimpleTimeline(t), func(enc *Encoder) {
+		if err := enc.SetMusicalGrid(120, 4); err != nil {
+			t.Fatalf("SetMusicalGrid failed: %v", err)
+		}
+		enc.SetShowGrid(true)
+	})
+
+	scale := float64(DefaultWidth-MarginLeft-MarginRight) / 10
+	for bar := 1; bar <= 6; bar++ {
+		x := MarginLeft + float64(bar-1)*2*scale
+		want := fmt.Sprintf(`<text x="%.2f" y="%.2f" text-anchor=`, x, MarginTop+RulerHeight/2.0)
+		i := strings.Index(svg, want)
+		if i < 0 || !strings.HasPrefix(svg[strings.Index(svg[i:], ">")+i:], fmt.Sprintf(">%d</text>", bar)) {
+			t.Errorf("Ruler missing bar %d at %.2f", bar, x)
+		}
+	}
+	if strings.Contains(svg, ">1.0s</text>") {
+		t.Error("Ruler should label bars, not seconds")
+	}
+
+	// Three beats between each pair of bars
+	if n := strings.Count(svg, `class="minor-tick"`); n != 15 {
+		t.Errorf("Expected 15 beat ticks, found %d", n)
+	}
+	if n := strings.Count(svg, `class="grid-line grid-minor"`); n != 15 {
+		t.Errorf("Expected 15 beat grid lines, found %d", n)
+	}
+	if n := strings.Count(svg, `class="grid-line"`); n != 6 {
+		t.Errorf("Expected 6 bar grid lines, found %d", n)
+	}
+	if beat := fmt.Sprintf(`x1="%.2f"`, MarginLeft+0.5*scale); !strings.Contains(svg, beat+` y1="100.00"`) {
+		t.Error("Missing beat grid line half a second in")
+	}
+
+	// Seconds-based grids have no minor lines
+	plain := encodeString(t, buildSimpleTimeline(t), func(enc *Encoder) {
+		enc.SetShowGrid(true)
+	})
+	if strings.Contains(plain, "grid-minor") {
+		t.Error("Minor grid lines drawn without a musical grid")
+	}
+}
+
+func TestMusicalGridViewRange(t *testing.T) {
+	// Cropped to 3s-9s, bars still start every 2s from the start of the
+	// timeline: bar 3 at 4s is the first tick, 1s into the view
+	svg := encodeString(t, buildSimpleTimeline(t), func(enc *Encoder) {
+		if err := enc.SetMusicalGrid(120, 4); err != nil {
+			t.Fatalf("SetMusicalGrid failed: %v", err)
+		}
+		enc.SetViewRange(opentime.NewTimeRange(opentime.NewRationalTime(72, 24), opentime.NewRationalTime(144, 24)))
+	})
+
+	scale := float64(DefaultWidth-MarginLeft-MarginRight) / 6
+	want := fmt.Sprintf(`<text x="%.2f" y="%.2f" text-anchor="middle" class="ruler-text" dominant-baseline="middle">3</text>`, MarginLeft+scale, MarginTop+RulerHeight/2.0)
+	if !strings.Contains(svg, want) {
+		t.Error("First bar in view should be bar 3, 1s in")
+	}
+	if strings.Contains(svg, `class="ruler-text" dominant-baseline="middle">2</text>`) {
+		t.Error("Bar 2 starts before the view")
+	}
+
+	// The beat at 3.5s is the first minor tick
+	if beat := fmt.Sprintf(`x1="%.2f"`, MarginLeft+0.5*scale); !strings.Contains(svg, beat+` y1="`+fmt.Sprintf("%.2f", MarginTop+RulerHeight*0.75)) {
+		t.Error("Beat ticks should stay on the beats of the timeline")
+	}
+}
+
+func TestMusicalScaleBar(t *testing.T) {
+	svg := encodeString(t, buildSimpleTimeline(t), func(enc *Encoder) {
+		if err := enc.SetMusicalGrid(120, 4); err != nil {
+			t.Fatalf("SetMusicalGrid failed: %v", err)
+		}
+		enc.SetRulerStyle(RulerStyleScaleBar)
+	})
+	if !strings.Contains(svg, ">1 bar</text>") {
+		t.Error("Scale bar should be labeled in bars")
+	}
+}
+
+func TestBarInterval(t *testing.T) {
+	enc := NewEncoder(&strings.Builder{})
+	if err := enc.SetMusicalGrid(120, 4); err != nil {
+		t.Fatalf("SetMusicalGrid failed: %v", err)
+	}
+	tests := []struct {
+		duration float64
+		expected float64
+	}{
+		{10, 2},   // 5 bars fit
+		{48, 4},   // 24 bars, every other one labeled
+		{200, 32}, // 100 bars, every 16th labeled
+		{0.5, 2},  // shorter than a bar
+	}
+	for _, tt := range tests {
+		l := &layout{durationSeconds: tt.duration, contentWidth: 1060}
+		if result, ok := enc.barInterval(l); !ok || result != tt.expected {
+			t.Errorf("barInterval(%v) = %v, %v, want %v", tt.duration, result, ok, tt.expected)
+		}
+	}
+}
+
+func TestBarIntervalBounded(t *testing.T) {
+	// Bars far shorter than the timeline stop the search rather than
+	// overflowing, leaving the ruler in seconds
+	enc := NewEncoder(&strings.Builder{})
+	enc.bpm, enc.beatsPerBar = 1e22, 4
+	l := &layout{durationSeconds: 1e6, contentWidth: 1060, timeScale: 1060 / 1e6}
+	if interval, ok := enc.barInterval(l); ok {
+		t.Errorf("barInterval = %v, want no bar interval", interval)
+	}
+	if interval := enc.rulerInterval(l); !(interval > 0) || l.durationSeconds/interval > enc.rulerTargetMarks(l) {
+		t.Errorf("rulerInterval = %v, want a positive interval giving at most the target marks", interval)
+	}
+	if divisions := enc.minorTickDivisions(l); divisions < 1 {
+		t.Errorf("minorTickDivisions = %d, want at least 1", divisions)
+	}
+
+	// A long timeline at the fastest tempo still renders
+	timeline := buildSimpleTimeline(t)
+	svg := encodeString(t, timeline, func(enc *Encoder) {
+		if err := enc.SetMusicalGrid(maxBPM, 1); err != nil {
+			t.Fatalf("SetMusicalGrid failed: %v", err)
+		}
+	})
+	if !strings.Contains(svg, `class="ruler-text"`) {
+		t.Error("Ruler missing labels at the fastest tempo")
+	}
+}
+
+// buildLongTimeline returns a timeline of one clip lasting hours at 24fps.
+func buildLongTimeline(t *testing.T, hours float64) *gotio.Timeline {
+	t.Helper()
+	timeline := gotio.NewTimeline("Long", nil, nil)
+	track := gotio.NewTrack("V1", nil, gotio.TrackKindVideo, nil, nil)
+	if err := track.AppendChild(newTestClip("Score", hours*3600*24, 24)); err != nil {
+		t.Fatalf("Failed to append clip: %v", err)
+	}
+	if err := timeline.Tracks().AppendChild(track); err != nil {
+		t.Fatalf("Failed to append track: %v", err)
+	}
+	return timeline
+}
+
+func TestMusicalGridCrowdedBars(t *testing.T) {
+	// Bars a fraction of a pixel apart get no minor ticks or grid lines
+	maxMinor := (DefaultWidth - MarginLeft - MarginRight) / int(minBeatSpacing)
+	for _, tt := range []struct {
+		bpm         float64
+		beatsPerBar int
+		hours       float64
+	}{
+		{120, 4, 2},
+		{maxBPM, 1, 10},
+	} {
+		svg := encodeString(t, buildLongTimeline(t, tt.hours), func(enc *Encoder) {
+			if err := enc.SetMusicalGrid(tt.bpm, tt.beatsPerBar); err != nil {
+				t.Fatalf("SetMusicalGrid failed: %v", err)
+			}
+			enc.SetShowGrid(true)
+		})
+		ticks := strings.Count(svg, `class="minor-tick"`)
+		lines := strings.Count(svg, `class="grid-line grid-minor"`)
+		if ticks > maxMinor || lines > maxMinor {
+			t.Errorf("%v bpm over %vh: %d minor ticks and %d minor grid lines, want at most %d",
+				tt.bpm, tt.hours, ticks, lines, maxMinor)
+		}
+	}
+}
+
+func TestMusicalGridFallback(t *testing.T) {
+	// At 1000 bpm in 1/4 even 2^20 bars are too many marks over 1000
+	// hours, so the ruler stays in seconds, without bar numbers
+	timeline := buildLongTimeline(t, 1000)
+	svg := encodeString(t, timeline, func(enc *Encoder) {
+		if err := enc.SetMusicalGrid(maxBPM, 1); err != nil {
+			t.Fatalf("SetMusicalGrid failed: %v", err)
+		}
+	})
+	if strings.Contains(svg, `class="ruler-text" dominant-baseline="middle">1</text>`) {
+		t.Error("Ticks off the bars should not be labeled with bar numbers")
+	}
+	if strings.Contains(svg, "grid-minor") || strings.Contains(svg, " bar</text>") {
+		t.Error("Ruler should fall back to seconds")
+	}
+
+	svg = encodeString(t, timeline, func(enc *Encoder) {
+		if err := enc.SetMusicalGrid(maxBPM, 1); err != nil {
+			t.Fatalf("SetMusicalGrid failed: %v", err)
+		}
+		enc.SetDualRuler(true)
+	})
+	if !strings.Contains(svg, `class="ruler-text" dominant-baseline="middle">00:00:00:00</text>`) {
+		t.Error("Dual ruler should keep timecode on its top row")
+	}
+}
+
+func TestMusicalDualRuler(t *testing.T) {
+	svg := encodeString(t, buildSimpleTimeline(t), func(enc *Encoder) {
+		if err := enc.SetMusicalGrid(120, 4); err != nil {
+			t.Fatalf("SetMusicalGrid failed: %v", err)
+		}
+		enc.SetDualRuler(true)
+	})
+
+	// Timecode above the bar number at bar 2, 2s in
+	x := MarginLeft + 2*float64(DefaultWidth-MarginLeft-MarginRight)/10
+	for _, label := range []struct {
+		text string
+		y    float64
+	}{
+		{"00:00:02:00", MarginTop + RulerHeight*0.5},
+		{"2", MarginTop + RulerHeight*1.5},
+	} {
+		want := fmt.Sprintf(`<text x="%.2f" y="%.2f" text-anchor="middle" class="ruler-text" dominant-baseline="middle">%s</text>`, x, label.y, label.text)
+		if !strings.Contains(svg, want) {
+			t.Errorf("Dual ruler missing %s at %.2f, %.2f", label.text, x, label.y)
+		}
+	}
+}
@@ -0,0 +1,133 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Contributors to the OpenTimelineIO project
+
+package svg
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/Avalanche-io/gotio"
+)
+
+const (
+	// nestedBadgeSize is the size of the icon marking nested clips.
+	nestedBadgeSize = 10.0
+	// maxNestedLanes is the most tracks of a nested timeline drawn in its
+	// clip's miniature.
+	maxNestedLanes = 4
+	// nestedLaneHeight is the tallest a lane of a miniature is drawn.
+	nestedLaneHeight = 4.0
+)
+
+// nestedExtensions are the file extensions of OTIO timelines, which mark
+// media references to nested timelines.
+var nestedExtensions = []string{".otio", ".otioz", ".otiod"}
+
+// NestedTimelineProvider resolves the timelines nested in clips.
+type NestedTimelineProvider interface {
+	// NestedTimeline returns the timeline a clip references, or false if it
+	// doesn't reference one or it can't be loaded.
+	NestedTimeline(clip *gotio.Clip) (*gotio.Timeline, bool)
+}
+
+// referencesTimeline reports whether a clip's external media reference
+// points to an OTIO file.
+func referencesTimeline(clip *gotio.Clip) bool {
+	url := mediaURL(clip)
+	if url == "" {
+		return false
+	}
+	ext := strings.ToLower(path.Ext(strings.TrimRight(url, "/")))
+	for _, nested := range nestedExtensions {
+		if ext == nested {
+			return true
+		}
+	}
+	return false
+}
+
+// nestedTimeline returns the timeline nested in a clip, if the nested
+// timeline provider has it, and whether the clip is nested at all.
+func (e *Encoder) nestedTimeline(clip *gotio.Clip) (*gotio.Timeline, bool) {
+	if e.nestedProvider != nil {
+		if timeline, ok := e.nestedProvider.NestedTimeline(clip); ok && timeline != nil {
+			return timeline, true
+		}
+	}
+	return nil, referencesTimeline(clip)
+}
+
+// nestedBadgePath returns the path of an icon in a nestedBadgeSize square
+// at x, y: three staggered bars, like the tracks of a timeline.
+func nestedBadgePath(x, y float64) string {
+	s := nestedBadgeSize
+	return fmt.Sprintf("M %.2f %.2f h %.2f M %.2f %.2f h %.2f M %.2f %.2f h %.2f",
+		x, y+s*0.2, s*0.6, x+s*0.2, y+s*0.5, s*0.8, x+s*0.1, y+s*0.8, s*0.5)
+}
+
+// drawNested marks a clip referencing a nested timeline with a badge in its
+// top-right corner and, if expanding nested timelines, draws a miniature of
+// the nested timeline along the bottom of the clip.
+func (e *Encoder) drawNested(builder *SVGBuilder, l *layout, clip *gotio.Clip, x, y, width, height float64, color string) error {
+	nested, ok := e.nestedTimeline(clip)
+	if !ok {
+		return nil
+	}
+
+	if width >= nestedBadgeSize+2*labelPadding && height >= nestedBadgeSize+2*labelPadding {
+		title := "Nested timeline"
+		if nested != nil && nested.Name() != "" {
+			title += ": " + nested.Name()
+		}
+		d := nestedBadgePath(x+width-labelPadding-nestedBadgeSize, y+labelPadding)
+		if err := builder.WritePathWithTitle(d, "none", color, 1.5, "", "nested-badge", title); err != nil {
+			return err
+		}
+	}
+
+	if !e.expandNested || nested == nil || l.vertical {
+		return nil
+	}
+	return e.drawNestedMiniature(builder, nested, x+labelPadding, y, width-2*labelPadding, height-labelPadding, color)
+}
+
+// drawNestedMiniature draws the clips of a nested timeline's tracks, one
+// thin lane each, at the bottom of the area at x, y spanning the
+// timeline's duration across its width.
+func (e *Encoder) drawNestedMiniature(builder *SVGBuilder, nested *gotio.Timeline, x, y, width, height float64, color string) error {
+	duration, err := nested.Duration()
+	if err != nil || !(duration.ToSeconds() > 0) || width <= 0 {
+		return nil
+	}
+	var lanes []*gotio.Track
+	if tracks := nested.Tracks(); tracks != nil {
+		lanes = e.laneTracks(tracks.Children())
+	}
+	if len(lanes) > maxNestedLanes {
+		lanes = lanes[:maxNestedLanes]
+	}
+	if len(lanes) == 0 {
+		return nil
+	}
+
+	// Keep to the bottom third of the clip, below its label
+	laneHeight := min(nestedLaneHeight, height/3/float64(len(lanes)))
+	if laneHeight < 1 {
+		return nil
+	}
+	top := y + height - float64(len(lanes))*laneHeight
+
+	if err := builder.StartGroup("", "nested-miniature"); err != nil {
+		return err
+	}
+	scale := width / duration.ToSeconds()
+	for i, track := range lanes {
+		laneY := top + float64(i)*laneHeight
+		if err := e.drawOverviewItems(builder, track, x, laneY, laneHeight-0.5, scale, color, "nested-clip"); err != nil {
+			return err
+		}
+	}
+	return builder.EndGroup()
+}
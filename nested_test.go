@@ -0,0 +1,149 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Contributors to the OpenTimelineIO project
+
+package svg
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/Avalanche-io/gotio"
+	"github.com/Avalanche-io/gotio/opentime"
+)
+
+// nestedByName provides nested timelines for clips by name.
+type nestedByName map[string]*gotio.Timeline
+
+func (p nestedByName) NestedTimeline(clip *gotio.Clip) (*gotio.Timeline, bool) {
+	timeline, ok := p[clip.Name()]
+	return timeline, ok
+}
+
+// newReferenceClip returns a 4 second clip with an external reference to
+// url.
+func newReferenceClip(name, url string) *gotio.Clip {
+	sr := opentime.NewTimeRange(opentime.NewRationalTime(0, 24), opentime.NewRationalTime(96, 24))
+	ref := gotio.NewExternalReference("", url, nil, nil)
+	return gotio.NewClip(name, ref, &sr, nil, nil, nil, "", nil)
+}
+
+func TestReferencesTimeline(t *testing.T) {
+	tests := []struct {
+		url      string
+		expected bool
+	}{
+		{"file:///projects/reel1.otio", true},
+		{"file:///projects/REEL1.OTIOZ", true},
+		{"file:///projects/reel1.otiod/", true},
+		{"file:///media/shot.mov", false},
+		{"", false},
+	}
+
+	for _, tt := range tests {
+		if got := referencesTimeline(newReferenceClip("Clip", tt.url)); got != tt.expected {
+			t.Errorf("referencesTimeline(%q) = %v, want %v", tt.url, got, tt.expected)
+		}
+	}
+}
+
+// buildNestedTimeline returns a timeline with a clip referencing an OTIO
+// file, named Reel, beside a plain clip, and the timeline Reel references,
+// with two tracks holding three clips.
+func buildNestedTimeline(t *testing.T) (*gotio.Timeline, *gotio.Timeline) {
+	t.Helper()
+
+	timeline := gotio.NewTimeline("Conform", nil, nil)
+	track := gotio.NewTrack("V1", nil, gotio.TrackKindVideo, nil, nil)
+	for _, clip := range []*gotio.Clip{
+		newReferenceClip("Reel", "file:///projects/reel1.otio"),
+		newReferenceClip("Shot", "file:///media/shot.mov"),
+	} {
+		if err := track.AppendChild(clip); err != nil {
+			t.Fatalf("Failed to append clip: %v", err)
+		}
+	}
+	if err := timeline.Tracks().AppendChild(track); err != nil {
+		t.Fatalf("Failed to append track: %v", err)
+	}
+
+	reel := gotio.NewTimeline("Reel 1", nil, nil)
+	v1 := gotio.NewTrack("V1", nil, gotio.TrackKindVideo, nil, nil)
+	a1 := gotio.NewTrack("A1", nil, gotio.TrackKindAudio, nil, nil)
+	for _, item := range []struct {
+		track *gotio.Track
+		clip  *gotio.Clip
+	}{
+		{v1, newTestClip("A", 48, 24)},
+		{v1, newTestClip("B", 48, 24)},
+		{a1, newTestClip("Music", 96, 24)},
+	} {
+		if err := item.track.AppendChild(item.clip); err != nil {
+			t.Fatalf("Failed to append clip: %v", err)
+		}
+	}
+	for _, track := range []*gotio.Track{v1, a1} {
+		if err := reel.Tracks().AppendChild(track); err != nil {
+			t.Fatalf("Failed to append track: %v", err)
+		}
+	}
+	return timeline, reel
+}
+
+func TestNestedBadge(t *testing.T) {
+	timeline, reel := buildNestedTimeline(t)
+
+	// Clips referencing OTIO files are badged without a provider, but
+	// can't be expanded
+	svg := encodeString(t, timeline, func(enc *Encoder) {
+		enc.SetExpandNested(true)
+	})
+	if n := strings.Count(svg, `class="nested-badge"`); n != 1 {
+		t.Errorf("Expected 1 nested badge, found %d", n)
+	}
+	if !strings.Contains(svg, "<title>Nested timeline</title>") {
+		t.Error("SVG missing nested badge tooltip")
+	}
+	if strings.Contains(svg, `class="nested-miniature"`) {
+		t.Error("Nested timeline expanded without a provider")
+	}
+
+	// The provider names the nested timeline
+	svg = encodeString(t, timeline, func(enc *Encoder) {
+		enc.SetNestedTimelineProvider(nestedByName{"Reel": reel})
+	})
+	if !strings.Contains(svg, "<title>Nested timeline: Reel 1</title>") {
+		t.Error("SVG missing nested timeline name")
+	}
+	if strings.Contains(svg, `class="nested-miniature"`) {
+		t.Error("Nested timeline expanded by default")
+	}
+}
+
+func TestExpandNested(t *testing.T) {
+	timeline, reel := buildNestedTimeline(t)
+
+	svg := encodeString(t, timeline, func(enc *Encoder) {
+		enc.SetNestedTimelineProvider(nestedByName{"Reel": reel})
+		enc.SetExpandNested(true)
+	})
+
+	if n := strings.Count(svg, `class="nested-miniature"`); n != 1 {
+		t.Fatalf("Expected 1 nested miniature, found %d", n)
+	}
+	if n := strings.Count(svg, `class="nested-clip"`); n != 3 {
+		t.Errorf("Expected 3 clips in the miniature, found %d", n)
+	}
+
+	// Nested timelines aren't expanded when time runs down the canvas
+	svg = encodeString(t, timeline, func(enc *Encoder) {
+		enc.SetNestedTimelineProvider(nestedByName{"Reel": reel})
+		enc.SetExpandNested(true)
+		enc.SetOrientation(OrientationVertical)
+	})
+	if strings.Contains(svg, `class="nested-miniature"`) {
+		t.Error("Nested timeline expanded in a vertical layout")
+	}
+	if !strings.Contains(svg, `class="nested-badge"`) {
+		t.Error("Nested timeline not badged in a vertical layout")
+	}
+}
@@ -0,0 +1,71 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Contributors to the OpenTimelineIO project
+
+package svg
+
+import "fmt"
+
+// Option configures an Encoder. Options are passed to NewEncoder.
+type Option func(*Encoder) error
+
+// WithSize sets the SVG canvas size in pixels.
+func WithSize(width, height int) Option {
+	return func(e *Encoder) error {
+		if width <= 0 || height <= 0 {
+			return fmt.Errorf("invalid size %dx%d: dimensions must be positive", width, height)
+		}
+		e.width = width
+		e.height = height
+		return nil
+	}
+}
+
+// WithMargins sets the margins around the timeline content, in pixels.
+func WithMargins(top, right, bottom, left int) Option {
+	return func(e *Encoder) error {
+		if top < 0 || right < 0 || bottom < 0 || left < 0 {
+			return fmt.Errorf("invalid margins (%d, %d, %d, %d): margins must not be negative", top, right, bottom, left)
+		}
+		e.marginTop = top
+		e.marginRight = right
+		e.marginBottom = bottom
+		e.marginLeft = left
+		return nil
+	}
+}
+
+// WithTrackHeight sets the maximum height of a track lane, in pixels.
+func WithTrackHeight(height int) Option {
+	return func(e *Encoder) error {
+		if height <= 0 {
+			return fmt.Errorf("invalid track height %d: height must be positive", height)
+		}
+		e.trackHeight = height
+		return nil
+	}
+}
+
+// WithTrackColorKey sets the track metadata key holding a track's display
+// color, as a hex string such as "#FF8800". Tracks with a color under the key
+// have their background and clips drawn in it instead of the theme color for
+// their kind. The default is DefaultTrackColorKey; an empty key ignores track
+// metadata.
+func WithTrackColorKey(key string) Option {
+	return func(e *Encoder) error {
+		e.trackColorKey = key
+		return nil
+	}
+}
+
+// WithTrackStateKeys sets the track metadata keys marking a track muted or
+// locked, with a value of true or "true". Muted and locked tracks get an
+// icon beside their label, and muted tracks have their items dimmed. The
+// defaults are DefaultMuteKey and DefaultLockKey; an empty key ignores that
+// state.
+func WithTrackStateKeys(muteKey, lockKey string) Option {
+	return func(e *Encoder) error {
+		e.muteKey = muteKey
+		e.lockKey = lockKey
+		return nil
+	}
+}
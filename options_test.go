@@ -0,0 +1,119 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Contributors to the OpenTimelineIO project
+
+package svg
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/Avalanche-io/gotio"
+)
+
+func TestOptions(t *testing.T) {
+	var buf bytes.Buffer
+	enc := NewEncoder(&buf,
+		WithSize(800, 400),
+		WithMargins(10, 20, 30, 40),
+		WithTrackHeight(50),
+	)
+
+	if enc.err != nil {
+		t.Fatalf("Unexpected option error: %v", enc.err)
+	}
+
+	if enc.width != 800 || enc.height != 400 {
+		t.Errorf("Expected size 800x400, got %dx%d", enc.width, enc.height)
+	}
+
+	if enc.marginTop != 10 || enc.marginRight != 20 || enc.marginBottom != 30 || enc.marginLeft != 40 {
+		t.Errorf("Unexpected margins: %d %d %d %d", enc.marginTop, enc.marginRight, enc.marginBottom, enc.marginLeft)
+	}
+
+	if enc.trackHeight != 50 {
+		t.Errorf("Expected track height 50, got %d", enc.trackHeight)
+	}
+}
+
+func TestInvalidOptions(t *testing.T) {
+	tests := []struct {
+		name string
+		opt  Option
+	}{
+		{"zero size", WithSize(0, 400)},
+		{"negative margin", WithMargins(10, -1, 10, 10)},
+		{"zero track height", WithTrackHeight(0)},
+	}
+
+	for _, tt := range tests {
+		var buf bytes.Buffer
+		enc := NewEncoder(&buf, tt.opt)
+
+		err := enc.Encode(buildSimpleTimeline(t))
+		if err == nil {
+			t.Errorf("%s: expected error", tt.name)
+			continue
+		}
+
+		if !strings.Contains(err.Error(), "invalid") {
+			t.Errorf("%s: unexpected error message: %v", tt.name, err)
+		}
+
+		if buf.Len() != 0 {
+			t.Errorf("%s: expected no output, got %d bytes", tt.name, buf.Len())
+		}
+	}
+}
+
+func TestSetSizeAfterOptions(t *testing.T) {
+	var buf bytes.Buffer
+	enc := NewEncoder(&buf, WithSize(800, 400))
+	enc.SetSize(640, 480)
+
+	if enc.width != 640 || enc.height != 480 {
+		t.Errorf("Expected size 640x480, got %dx%d", enc.width, enc.height)
+	}
+}
+
+func TestWithTrackColorKey(t *testing.T) {
+	timeline := gotio.NewTimeline("Colors", nil, nil)
+	tracks := []*gotio.Track{
+		gotio.NewTrack("Picture", nil, gotio.TrackKindVideo, gotio.AnyDictionary{"color": "#AA0000", "display_color": "#00AA00"}, nil),
+		gotio.NewTrack("Sound", nil, gotio.TrackKindAudio, nil, nil),
+	}
+	for _, track := range tracks {
+		if err := track.AppendChild(newTestClip("Clip "+track.Name(), 24, 24)); err != nil {
+			t.Fatalf("Failed to append clip: %v", err)
+		}
+		if err := timeline.Tracks().AppendChild(track); err != nil {
+			t.Fatalf("Failed to append track: %v", err)
+		}
+	}
+
+	encode := func(opts ...Option) string {
+		var buf bytes.Buffer
+		if err := NewEncoder(&buf, opts...).Encode(timeline); err != nil {
+			t.Fatalf("Encode failed: %v", err)
+		}
+		return buf.String()
+	}
+
+	svg := encode()
+	if !strings.Contains(svg, `fill="#AA000033"`) || !strings.Contains(svg, `fill="#AA0000" stroke="#333333" id="clip-Clip_Picture"`) {
+		t.Error("Track color from metadata not used for the background and clips")
+	}
+	if !strings.Contains(svg, `fill="`+AudioTrackColor+`" stroke="#333333" id="clip-Clip_Sound"`) {
+		t.Error("Track without a color should keep the theme color")
+	}
+
+	svg = encode(WithTrackColorKey("display_color"))
+	if !strings.Contains(svg, `fill="#00AA00" stroke="#333333" id="clip-Clip_Picture"`) {
+		t.Error("Track color not read from the configured key")
+	}
+
+	svg = encode(WithTrackColorKey(""))
+	if strings.Contains(svg, "#AA0000") || strings.Contains(svg, "#00AA00") {
+		t.Error("An empty key should ignore track metadata")
+	}
+}
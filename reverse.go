@@ -0,0 +1,61 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Contributors to the OpenTimelineIO project
+
+package svg
+
+import (
+	"fmt"
+
+	"github.com/Avalanche-io/gotio"
+	"github.com/Avalanche-io/gotio/opentime"
+)
+
+// reverseMarkerSize is the size of the arrow marking reversed clips.
+const reverseMarkerSize = 8.0
+
+// isReversed reports whether a clip plays its media backwards: its source
+// range has a negative duration, or one of its time warps has a negative
+// rate.
+func isReversed(clip *gotio.Clip) bool {
+	if sr := clip.SourceRange(); sr != nil && sr.Duration().Value() < 0 {
+		return true
+	}
+	for _, effect := range clip.Effects() {
+		if warp, ok := effect.(*gotio.LinearTimeWarp); ok && warp.TimeScalar() < 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// forwardRange returns a range with a negative duration flipped to run
+// forwards from the same start, so items with one are drawn at their length
+// rather than as an empty or inverted rectangle.
+func forwardRange(rng opentime.TimeRange) opentime.TimeRange {
+	duration := rng.Duration()
+	if duration.Value() >= 0 {
+		return rng
+	}
+	return opentime.NewTimeRange(rng.StartTime(), opentime.NewRationalTime(-duration.Value(), duration.Rate()))
+}
+
+// reverseMarkerPath returns the path of an arrow in a reverseMarkerSize
+// square at x, y pointing back along the time axis: left, or up if
+// vertical.
+func reverseMarkerPath(x, y float64, vertical bool) string {
+	s := reverseMarkerSize
+	if vertical {
+		return fmt.Sprintf("M %.2f %.2f L %.2f %.2f L %.2f %.2f Z", x+s/2, y, x+s, y+s, x, y+s)
+	}
+	return fmt.Sprintf("M %.2f %.2f L %.2f %.2f L %.2f %.2f Z", x, y+s/2, x+s, y, x+s, y+s)
+}
+
+// drawReverseMarker draws an arrow in the top-left corner of a reversed clip
+// at x, y, if the clip is large enough to hold it.
+func (e *Encoder) drawReverseMarker(builder *SVGBuilder, l *layout, x, y, width, height float64, fill string) error {
+	if width < reverseMarkerSize+2*labelPadding || height < reverseMarkerSize+2*labelPadding {
+		return nil
+	}
+	d := reverseMarkerPath(x+labelPadding, y+labelPadding, l.vertical)
+	return builder.WritePathWithTitle(d, fill, "", 0, "", "reverse-marker", "Reversed")
+}
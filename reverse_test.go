@@ -0,0 +1,112 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Contributors to the OpenTimelineIO project
+
+package svg
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/Avalanche-io/gotio"
+	"github.com/Avalanche-io/gotio/opentime"
+)
+
+// newReversedClip returns a clip whose source range runs backwards for
+// frames from frame start, at 24 fps.
+func newReversedClip(name string, start, frames float64) *gotio.Clip {
+	sr := opentime.NewTimeRange(opentime.NewRationalTime(start, 24), opentime.NewRationalTime(-frames, 24))
+	return gotio.NewClip(name, nil, &sr, nil, nil, nil, "", nil)
+}
+
+func TestIsReversed(t *testing.T) {
+	sr := opentime.NewTimeRange(opentime.NewRationalTime(0, 24), opentime.NewRationalTime(48, 24))
+	warped := func(scalar float64) *gotio.Clip {
+		effects := []gotio.Effect{gotio.NewLinearTimeWarp("Warp", "LinearTimeWarp", scalar, nil)}
+		return gotio.NewClip("Clip", nil, &sr, nil, effects, nil, "", nil)
+	}
+
+	tests := []struct {
+		name     string
+		clip     *gotio.Clip
+		expected bool
+	}{
+		{"forward", newTestClip("Clip", 48, 24), false},
+		{"negative duration", newReversedClip("Clip", 48, 48), true},
+		{"reverse warp", warped(-1), true},
+		{"speed up", warped(2), false},
+		{"freeze", gotio.NewClip("Clip", nil, &sr, nil, []gotio.Effect{&gotio.FreezeFrame{}}, nil, "", nil), false},
+		{"no source range", gotio.NewClip("Clip", nil, nil, nil, nil, nil, "", nil), false},
+	}
+
+	for _, tt := range tests {
+		if got := isReversed(tt.clip); got != tt.expected {
+			t.Errorf("isReversed(%s) = %v, want %v", tt.name, got, tt.expected)
+		}
+	}
+}
+
+func TestForwardRange(t *testing.T) {
+	rng := forwardRange(opentime.NewTimeRange(opentime.NewRationalTime(48, 24), opentime.NewRationalTime(-24, 24)))
+	if rng.StartTime().Value() != 48 || rng.Duration().Value() != 24 || rng.Duration().Rate() != 24 {
+		t.Errorf("forwardRange = %v + %v, want 48 + 24 at 24 fps", rng.StartTime().Value(), rng.Duration().Value())
+	}
+}
+
+func TestReversedClipMarker(t *testing.T) {
+	sr := opentime.NewTimeRange(opentime.NewRationalTime(0, 24), opentime.NewRationalTime(120, 24))
+	effects := []gotio.Effect{gotio.NewLinearTimeWarp("Reverse", "LinearTimeWarp", -1, nil)}
+
+	timeline := gotio.NewTimeline("Reversed", nil, nil)
+	v1 := gotio.NewTrack("V1", nil, gotio.TrackKindVideo, nil, nil)
+	for _, clip := range []*gotio.Clip{
+		gotio.NewClip("Warped", nil, &sr, nil, effects, nil, "", nil),
+		newTestClip("Plain", 120, 24),
+	} {
+		if err := v1.AppendChild(clip); err != nil {
+			t.Fatalf("Failed to append clip: %v", err)
+		}
+	}
+	v2 := gotio.NewTrack("V2", nil, gotio.TrackKindVideo, nil, nil)
+	if err := v2.AppendChild(newReversedClip("Backward", 48, 48)); err != nil {
+		t.Fatalf("Failed to append clip: %v", err)
+	}
+	for _, track := range []*gotio.Track{v1, v2} {
+		if err := timeline.Tracks().AppendChild(track); err != nil {
+			t.Fatalf("Failed to append track: %v", err)
+		}
+	}
+
+	svg := encodeString(t, timeline, nil)
+
+	if n := strings.Count(svg, `class="reverse-marker"`); n != 2 {
+		t.Errorf("Expected 2 reverse markers, found %d", n)
+	}
+	if !strings.Contains(svg, "<title>Reversed</title>") {
+		t.Error("SVG missing reverse marker tooltip")
+	}
+
+	// The clip with a negative duration is drawn at its length, not as an
+	// inverted rectangle
+	if strings.Contains(svg, `width="-`) {
+		t.Error("SVG contains a negative width")
+	}
+	width := 2 * float64(DefaultWidth-MarginLeft-MarginRight) / 10
+	start := strings.Index(svg, `id="clip-Backward"`)
+	if start < 0 {
+		t.Fatal("SVG missing reversed clip")
+	}
+	rect := svg[strings.LastIndex(svg[:start], "<rect"):start]
+	if !strings.Contains(rect, fmt.Sprintf(`width="%.2f"`, width)) {
+		t.Error("Reversed clip not drawn at its length")
+	}
+}
+
+func TestReverseMarkerPath(t *testing.T) {
+	if got, want := reverseMarkerPath(10, 20, false), "M 10.00 24.00 L 18.00 20.00 L 18.00 28.00 Z"; got != want {
+		t.Errorf("reverseMarkerPath = %q, want %q", got, want)
+	}
+	if got, want := reverseMarkerPath(10, 20, true), "M 14.00 20.00 L 18.00 28.00 L 10.00 28.00 Z"; got != want {
+		t.Errorf("reverseMarkerPath(vertical) = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,61 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Contributors to the OpenTimelineIO project
+
+package svg
+
+import (
+	"fmt"
+	"math"
+)
+
+// RulerStyle selects how the time axis is shown.
+type RulerStyle int
+
+const (
+	// RulerStyleFull draws a ruler with labeled ticks above the tracks.
+	RulerStyleFull RulerStyle = iota
+	// RulerStyleScaleBar draws a single labeled scale bar below the tracks
+	// instead of a ruler, leaving more room for the tracks in small images.
+	RulerStyleScaleBar
+)
+
+// scaleBarHeight is the minimum bottom margin kept for the scale bar.
+const scaleBarHeight = 24
+
+// drawScaleBar draws a bracket below the tracks spanning one ruler interval,
+// labeled with its length.
+func (e *Encoder) drawScaleBar(builder *SVGBuilder, l *layout) error {
+	if err := builder.StartGroup(builder.UniqueID("scale-bar"), "scale-bar"); err != nil {
+		return err
+	}
+
+	interval := e.rulerInterval(l)
+	x0 := float64(e.marginLeft)
+	x1 := x0 + interval*l.timeScale
+	y := l.tracksBottom() + float64(l.marginBottom)/2
+
+	bracket := fmt.Sprintf("M %.2f %.2f V %.2f H %.2f V %.2f", x0, y-4, y, x1, y-4)
+	if err := builder.WritePath(bracket, "none", e.theme.Text, 1, "scale-bar-line"); err != nil {
+		return err
+	}
+
+	label := formatTimeAs(interval, e.timeFormat, l.rate)
+	if e.barGrid(l) {
+		label = plural(int(math.Round(interval/e.barSeconds())), "bar")
+	}
+	if err := builder.WriteText(x1+labelPadding, y-2, label, "start", "", "ruler-text"); err != nil {
+		return err
+	}
+
+	return builder.EndGroup()
+}
+
+// drawTotalDuration draws the label of the duration shown in the area at x,
+// y at the right end of the ruler, over a background hiding the ticks
+// behind it.
+func (e *Encoder) drawTotalDuration(builder *SVGBuilder, label string, x, y, width, height float64) error {
+	if err := builder.WriteRect(x, y+0.5, width, height-1, e.theme.TrackLabelBg, "", "", "ruler-total-bg", ""); err != nil {
+		return err
+	}
+	return builder.WriteText(x+width-labelPadding, y+height/2, label, "end", "", "ruler-text ruler-total")
+}
@@ -0,0 +1,255 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Contributors to the OpenTimelineIO project
+
+package svg
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/Avalanche-io/gotio"
+	"github.com/Avalanche-io/gotio/opentime"
+)
+
+func TestScaleBar(t *testing.T) {
+	svg := encodeString(t, buildSimpleTimeline(t), func(enc *Encoder) {
+		enc.SetAutoHeight(true)
+		enc.SetRulerStyle(RulerStyleScaleBar)
+	})
+
+	if strings.Contains(svg, `class="ruler"`) {
+		t.Error("Scale bar should replace the ruler")
+	}
+	if !strings.Contains(svg, `class="scale-bar"`) {
+		t.Fatal("SVG missing scale bar")
+	}
+
+	// A 10 second timeline gets a 1 second bar
+	contentWidth := float64(DefaultWidth - MarginLeft - MarginRight)
+	x1 := float64(MarginLeft) + contentWidth/10
+	if !strings.Contains(svg, fmt.Sprintf(`H %.2f`, x1)) {
+		t.Error("Scale bar has the wrong length")
+	}
+	if !strings.Contains(svg, ">1.0s</text>") {
+		t.Error("SVG missing scale bar label")
+	}
+
+	// The tracks move up into the space the ruler used
+	if !strings.Contains(svg, fmt.Sprintf(`y="%d.00" width="%.2f" height="%d.00" fill="%s33"`, MarginTop, contentWidth, TrackHeight, VideoTrackColor)) {
+		t.Error("Tracks should start right below the top margin")
+	}
+	if !strings.Contains(svg, fmt.Sprintf(`height="%d"`, MarginTop+TrackHeight+MarginBottom)) {
+		t.Error("Height should not include the ruler")
+	}
+}
+
+func TestScaleBarExpandsBottomMargin(t *testing.T) {
+	svg := encodeString(t, buildSimpleTimeline(t), func(enc *Encoder) {
+		enc.SetAutoHeight(true)
+		enc.SetRulerStyle(RulerStyleScaleBar)
+		if err := WithMargins(MarginTop, MarginRight, 0, MarginLeft)(enc); err != nil {
+			t.Fatalf("WithMargins failed: %v", err)
+		}
+	})
+
+	if !strings.Contains(svg, fmt.Sprintf(`height="%d"`, MarginTop+TrackHeight+scaleBarHeight)) {
+		t.Error("Bottom margin should grow to fit the scale bar")
+	}
+}
+
+func TestMinorTicks(t *testing.T) {
+	svg := encodeString(t, buildSimpleTimeline(t), nil)
+
+	// A 10 second timeline has major ticks every second, each divided
+	// into fifths
+	if n := strings.Count(svg, `class="tick"`); n != 11 {
+		t.Errorf("Expected 11 major ticks, found %d", n)
+	}
+	if n := strings.Count(svg, `class="minor-tick"`); n != 40 {
+		t.Errorf("Expected 40 minor ticks, found %d", n)
+	}
+
+	minorTop := float64(MarginTop) + RulerHeight*0.75
+	if !strings.Contains(svg, fmt.Sprintf(`y1="%.2f"`, minorTop)) {
+		t.Error("Minor ticks should be shorter than major ticks")
+	}
+}
+
+func TestMinorTickDivisions(t *testing.T) {
+	tests := []struct {
+		format   TimeFormat
+		seconds  float64
+		rate     float64
+		expected int
+	}{
+		{TimeFormatSeconds, 10, 24, 5},
+		{TimeFormatFrames, 10, 24, 4},  // 24 frame interval
+		{TimeFormatFrames, 10, 25, 5},  // 25 frame interval
+		{TimeFormatFrames, 0.5, 24, 1}, // 1 frame interval
+	}
+
+	for _, tt := range tests {
+		enc := NewEncoder(nil)
+		enc.SetTimeFormat(tt.format)
+		l := &layout{durationSeconds: tt.seconds, rate: tt.rate}
+		if result := enc.minorTickDivisions(l); result != tt.expected {
+			t.Errorf("minorTickDivisions(%v, %vs at %v) = %d, want %d", tt.format, tt.seconds, tt.rate, result, tt.expected)
+		}
+	}
+}
+
+func TestLastRulerLabelStaysInside(t *testing.T) {
+	// At 10.1s the 10s tick lands just short of the right edge, too close
+	// for a centered label
+	timeline := gotio.NewTimeline("", nil, nil)
+	track := gotio.NewTrack("V1", nil, gotio.TrackKindVideo, nil, nil)
+	if err := track.AppendChild(newTestClip("Shot", 242.4, 24)); err != nil {
+		t.Fatalf("Failed to append clip: %v", err)
+	}
+	if err := timeline.Tracks().AppendChild(track); err != nil {
+		t.Fatalf("Failed to append track: %v", err)
+	}
+
+	svg := encodeString(t, timeline, nil)
+
+	if !strings.Contains(svg, `text-anchor="end" class="ruler-text" dominant-baseline="middle">10.0s</text>`) {
+		t.Error("Last ruler label should be anchored to its end")
+	}
+	if !strings.Contains(svg, `text-anchor="middle" class="ruler-text" dominant-baseline="middle">9.0s</text>`) {
+		t.Error("Other ruler labels should stay centered")
+	}
+}
+
+func TestRulerTargetMarks(t *testing.T) {
+	enc := NewEncoder(nil)
+	for _, marks := range []int{0, -4} {
+		if err := enc.SetRulerTargetMarks(marks); err == nil {
+			t.Errorf("SetRulerTargetMarks(%d) should fail", marks)
+		}
+	}
+
+	tests := []struct {
+		name  string
+		setup func(*Encoder)
+		ticks int
+	}{
+		{"default", nil, 11},
+		{"sparse", func(enc *Encoder) {
+			if err := enc.SetRulerTargetMarks(4); err != nil {
+				t.Fatalf("SetRulerTargetMarks failed: %v", err)
+			}
+		}, 3}, // every 2.5s rounded up to 5s
+		{"dense", func(enc *Encoder) {
+			if err := enc.SetRulerTargetMarks(20); err != nil {
+				t.Fatalf("SetRulerTargetMarks failed: %v", err)
+			}
+		}, 21}, // every 0.5s
+		{"narrow canvas", func(enc *Encoder) {
+			enc.SetSize(MarginLeft+MarginRight+200, DefaultHeight)
+		}, 3}, // room for 4 marks, every 5s
+	}
+
+	for _, tt := range tests {
+		svg := encodeString(t, buildSimpleTimeline(t), tt.setup)
+		if n := strings.Count(svg, `class="tick"`); n != tt.ticks {
+			t.Errorf("%s: expected %d ticks, found %d", tt.name, tt.ticks, n)
+		}
+	}
+}
+
+func TestDualRuler(t *testing.T) {
+	plain := encodeString(t, buildSimpleTimeline(t), nil)
+	svg := encodeString(t, buildSimpleTimeline(t), func(enc *Encoder) {
+		enc.SetDualRuler(true)
+	})
+
+	if strings.Contains(plain, `class="ruler-divider"`) {
+		t.Error("Dual ruler drawn by default")
+	}
+	if !strings.Contains(svg, fmt.Sprintf(`height="%.2f" fill="%s" stroke="%s" class="ruler-bg"`, 2.0*RulerHeight, TrackLabelBg, GridColor)) {
+		t.Error("Dual ruler should be twice as tall")
+	}
+
+	// Both rows label the same tick, timecode above seconds
+	scale := float64(DefaultWidth-MarginLeft-MarginRight) / 10
+	x := MarginLeft + scale
+	for _, label := range []struct {
+		text string
+		y    float64
+	}{
+		{"00:00:01:00", MarginTop + RulerHeight*0.5},
+		{"1.0s", MarginTop + RulerHeight*1.5},
+	} {
+		want := fmt.Sprintf(`<text x="%.2f" y="%.2f" text-anchor="middle" class="ruler-text"`, x, label.y)
+		i := strings.Index(svg, want)
+		if i < 0 || !strings.HasPrefix(svg[strings.Index(svg[i:], ">")+i:], ">"+label.text+"</text>") {
+			t.Errorf("Ruler missing label %s at %.2f, %.2f", label.text, x, label.y)
+		}
+	}
+
+	// Tracks start below the taller ruler
+	trackY := fmt.Sprintf(`y="%.2f"`, float64(MarginTop+2*RulerHeight))
+	if !strings.Contains(svg, trackY+` width="1060.00" height="80.00"`) {
+		t.Error("Tracks should start below the dual ruler")
+	}
+
+	// A scale bar replaces the ruler either way
+	svg = encodeString(t, buildSimpleTimeline(t), func(enc *Encoder) {
+		enc.SetDualRuler(true)
+		enc.SetRulerStyle(RulerStyleScaleBar)
+	})
+	if strings.Contains(svg, `class="ruler-divider"`) {
+		t.Error("Dual ruler drawn with a scale bar")
+	}
+}
+
+func TestTotalDuration(t *testing.T) {
+	plain := encodeString(t, buildSimpleTimeline(t), nil)
+	if strings.Contains(plain, "ruler-total\"") {
+		t.Error("Total duration shown by default")
+	}
+
+	svg := encodeString(t, buildSimpleTimeline(t), func(enc *Encoder) {
+		enc.SetShowTotalDuration(true)
+	})
+	right := float64(DefaultWidth - MarginRight)
+	want := fmt.Sprintf(`<text x="%.2f" y="%.2f" text-anchor="end" class="ruler-text ruler-total" dominant-baseline="middle">10.0s</text>`,
+		right-labelPadding, MarginTop+RulerHeight/2.0)
+	if !strings.Contains(svg, want) {
+		t.Error("Ruler missing total duration label at its right end")
+	}
+	if !strings.Contains(svg, `class="ruler-total-bg"`) || strings.Index(svg, `class="ruler-total-bg"`) < strings.LastIndex(svg, `class="tick"`) {
+		t.Error("Total duration should be drawn over the ticks")
+	}
+
+	// The last tick's label gives way to the total
+	if !strings.Contains(plain, `class="ruler-text" dominant-baseline="middle">10.0s</text>`) {
+		t.Fatal("Expected a label on the last tick")
+	}
+	if strings.Contains(svg, `class="ruler-text" dominant-baseline="middle">10.0s</text>`) {
+		t.Error("Tick labels under the total duration should be skipped")
+	}
+	if !strings.Contains(svg, `class="ruler-text" dominant-baseline="middle">8.0s</text>`) {
+		t.Error("Tick labels clear of the total duration should stay")
+	}
+
+	// The label follows the time format and the span of the view range
+	svg = encodeString(t, buildSimpleTimeline(t), func(enc *Encoder) {
+		enc.SetShowTotalDuration(true)
+		enc.SetTimeFormat(TimeFormatTimecode)
+		enc.SetViewRange(opentime.NewTimeRange(opentime.NewRationalTime(48, 24), opentime.NewRationalTime(96, 24)))
+	})
+	if !strings.Contains(svg, `class="ruler-text ruler-total" dominant-baseline="middle">00:00:04:00</text>`) {
+		t.Error("Total duration should give the view range's length in timecode")
+	}
+
+	// It's drawn on the top row of a dual ruler
+	svg = encodeString(t, buildSimpleTimeline(t), func(enc *Encoder) {
+		enc.SetShowTotalDuration(true)
+		enc.SetDualRuler(true)
+	})
+	if !strings.Contains(svg, fmt.Sprintf(`y="%.2f" text-anchor="end" class="ruler-text ruler-total"`, MarginTop+RulerHeight/2.0)) {
+		t.Error("Total duration should be on the top row of a dual ruler")
+	}
+}
@@ -0,0 +1,23 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Contributors to the OpenTimelineIO project
+
+package svg
+
+import "github.com/Avalanche-io/gotio/opentime"
+
+// Stats describes what an Encode call drew.
+type Stats struct {
+	// Tracks is the number of track lanes drawn. Tracks nested in stacks
+	// are drawn within their parent's lane and are not counted.
+	Tracks int
+	// Clips, Gaps, and Transitions count the items drawn, including those
+	// nested in stacks.
+	Clips       int
+	Gaps        int
+	Transitions int
+	// Duration is the duration of the timeline.
+	Duration opentime.RationalTime
+	// Width and Height are the size of the SVG canvas in pixels.
+	Width  int
+	Height int
+}
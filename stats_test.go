@@ -0,0 +1,66 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Contributors to the OpenTimelineIO project
+
+package svg
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/Avalanche-io/gotio"
+	"github.com/Avalanche-io/gotio/opentime"
+)
+
+func TestEncodeWithStats(t *testing.T) {
+	timeline := gotio.NewTimeline("Stats", nil, nil)
+
+	video := gotio.NewTrack("V1", nil, gotio.TrackKindVideo, nil, nil)
+	half := opentime.NewRationalTime(12, 24)
+	children := []gotio.Composable{
+		newTestClip("Shot A", 48, 24),
+		gotio.NewTransition("Dissolve", gotio.TransitionTypeSMPTEDissolve, half, half, nil),
+		newTestClip("Shot B", 48, 24),
+		gotio.NewGapWithDuration(opentime.NewRationalTime(24, 24)),
+		newTestClip("Shot C", 48, 24),
+	}
+	for _, child := range children {
+		if err := video.AppendChild(child); err != nil {
+			t.Fatalf("Failed to append child: %v", err)
+		}
+	}
+
+	audio := gotio.NewTrack("A1", nil, gotio.TrackKindAudio, nil, nil)
+	if err := audio.AppendChild(newTestClip("Music", 168, 24)); err != nil {
+		t.Fatalf("Failed to append clip: %v", err)
+	}
+
+	for _, track := range []*gotio.Track{video, audio} {
+		if err := timeline.Tracks().AppendChild(track); err != nil {
+			t.Fatalf("Failed to append track: %v", err)
+		}
+	}
+
+	var buf bytes.Buffer
+	enc := NewEncoder(&buf)
+	enc.SetAutoHeight(true)
+	stats, err := enc.EncodeWithStats(timeline)
+	if err != nil {
+		t.Fatalf("EncodeWithStats failed: %v", err)
+	}
+
+	if stats.Tracks != 2 || stats.Clips != 4 || stats.Gaps != 1 || stats.Transitions != 1 {
+		t.Errorf("Unexpected counts: %+v", stats)
+	}
+	if stats.Duration.ToSeconds() != 7 {
+		t.Errorf("Expected duration 7s, got %v", stats.Duration.ToSeconds())
+	}
+	if stats.Width != DefaultWidth {
+		t.Errorf("Expected width %d, got %d", DefaultWidth, stats.Width)
+	}
+	if expected := MarginTop + RulerHeight + 2*TrackHeight + MarginBottom; stats.Height != expected {
+		t.Errorf("Expected height %d, got %d", expected, stats.Height)
+	}
+	if buf.Len() == 0 {
+		t.Error("EncodeWithStats wrote no SVG")
+	}
+}
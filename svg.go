@@ -13,26 +13,99 @@ import (
 type SVGBuilder struct {
 	w      io.Writer
 	indent int
+	ids    map[string]bool
+	prefix string
+
+	// compact omits indentation and line breaks between elements.
+	compact bool
+	// omitDeclaration leaves out the XML declaration, for inline embedding.
+	omitDeclaration bool
+	// responsive leaves out the fixed width and height, so the SVG scales
+	// to its container.
+	responsive bool
 }
 
 // NewSVGBuilder creates a new SVG builder.
 func NewSVGBuilder(w io.Writer) *SVGBuilder {
-	return &SVGBuilder{w: w, indent: 0}
+	return &SVGBuilder{w: w, indent: 0, ids: make(map[string]bool)}
+}
+
+// SetIDPrefix sets a prefix added to every id returned by UniqueID, so that
+// several documents can be embedded in one page without id collisions.
+func (b *SVGBuilder) SetIDPrefix(prefix string) {
+	b.prefix = prefix
+}
+
+// SetCompact sets whether elements are written without indentation or line
+// breaks between them, for smaller output.
+func (b *SVGBuilder) SetCompact(compact bool) {
+	b.compact = compact
+}
+
+// SetOmitDeclaration sets whether the XML declaration is left out of the
+// header, as required when the SVG is embedded inline in HTML.
+func (b *SVGBuilder) SetOmitDeclaration(omit bool) {
+	b.omitDeclaration = omit
+}
+
+// SetResponsive sets whether the header omits the width and height
+// attributes, keeping only the viewBox so the SVG scales to fit its
+// container.
+func (b *SVGBuilder) SetResponsive(responsive bool) {
+	b.responsive = responsive
+}
+
+// UniqueID returns id, or id with a numeric suffix ("-2", "-3", ...) if it
+// has already been returned for this document, after the id prefix.
+func (b *SVGBuilder) UniqueID(id string) string {
+	unique := id
+	for n := 2; b.ids[unique]; n++ {
+		unique = fmt.Sprintf("%s-%d", id, n)
+	}
+	b.ids[unique] = true
+	return b.prefix + unique
 }
 
 // WriteHeader writes the SVG header with dimensions.
 func (b *SVGBuilder) WriteHeader(width, height int) error {
-	_, err := fmt.Fprintf(b.w, `<?xml version="1.0" encoding="UTF-8"?>
-<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d">
-`, width, height, width, height)
+	if !b.omitDeclaration {
+		if err := b.writeLine(`<?xml version="1.0" encoding="UTF-8"?>`); err != nil {
+			return err
+		}
+	}
+	var err error
+	if b.responsive {
+		err = b.writeLine(`<svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 %d %d" preserveAspectRatio="xMidYMid meet">`,
+			width, height)
+	} else {
+		err = b.writeLine(`<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d">`,
+			width, height, width, height)
+	}
 	b.indent = 1
 	return err
 }
 
+// WriteTitleDesc writes document-level <title> and <desc> elements. They
+// should directly follow the header for accessibility tools to find them.
+// Empty values are omitted.
+func (b *SVGBuilder) WriteTitleDesc(title, desc string) error {
+	if title != "" {
+		if err := b.writeLine("<title>%s</title>", escapeText(title)); err != nil {
+			return err
+		}
+	}
+	if desc != "" {
+		if err := b.writeLine("<desc>%s</desc>", escapeText(desc)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // WriteFooter writes the closing SVG tag.
 func (b *SVGBuilder) WriteFooter() error {
-	_, err := fmt.Fprintf(b.w, "</svg>\n")
-	return err
+	b.indent = 0
+	return b.writeLine("</svg>")
 }
 
 // StartGroup starts a group element.
@@ -44,7 +117,29 @@ func (b *SVGBuilder) StartGroup(id, class string) error {
 	if class != "" {
 		attrs += fmt.Sprintf(` class="%s"`, escapeAttr(class))
 	}
-	_, err := fmt.Fprintf(b.w, "%s<g%s>\n", indent(b.indent), attrs)
+	err := b.writeLine("<g%s>", attrs)
+	b.indent++
+	return err
+}
+
+// StartGroupAt starts a group element whose contents are offset by x, y.
+func (b *SVGBuilder) StartGroupAt(id, class string, x, y float64) error {
+	attrs := ""
+	if id != "" {
+		attrs += fmt.Sprintf(` id="%s"`, escapeAttr(id))
+	}
+	if class != "" {
+		attrs += fmt.Sprintf(` class="%s"`, escapeAttr(class))
+	}
+	err := b.writeLine(`<g%s transform="translate(%.2f %.2f)">`, attrs, x, y)
+	b.indent++
+	return err
+}
+
+// StartClippedGroup starts a group element clipped to the clip path with
+// the given id.
+func (b *SVGBuilder) StartClippedGroup(clipID string) error {
+	err := b.writeLine(`<g clip-path="url(#%s)">`, escapeAttr(clipID))
 	b.indent++
 	return err
 }
@@ -52,62 +147,179 @@ func (b *SVGBuilder) StartGroup(id, class string) error {
 // EndGroup ends a group element.
 func (b *SVGBuilder) EndGroup() error {
 	b.indent--
-	_, err := fmt.Fprintf(b.w, "%s</g>\n", indent(b.indent))
+	return b.writeLine("</g>")
+}
+
+// StartAnchor starts a link element pointing at href.
+func (b *SVGBuilder) StartAnchor(href string) error {
+	return b.StartAnchorWithLabel(href, "")
+}
+
+// StartAnchorWithLabel starts a link element pointing at href, with an
+// aria-label describing where it leads for assistive technology.
+func (b *SVGBuilder) StartAnchorWithLabel(href, label string) error {
+	attrs := fmt.Sprintf(`href="%s"`, escapeAttr(href))
+	if label != "" {
+		attrs += fmt.Sprintf(` aria-label="%s"`, escapeAttr(label))
+	}
+	err := b.writeLine("<a %s>", attrs)
+	b.indent++
 	return err
 }
 
+// EndAnchor ends a link element.
+func (b *SVGBuilder) EndAnchor() error {
+	b.indent--
+	return b.writeLine("</a>")
+}
+
 // WriteRect writes a rectangle element.
 func (b *SVGBuilder) WriteRect(x, y, width, height float64, fill, stroke string, id, class, text string) error {
-	attrs := fmt.Sprintf(`x="%.2f" y="%.2f" width="%.2f" height="%.2f"`, x, y, width, height)
+	attrs := rectAttrs(x, y, width, height, fill, stroke, id, class)
+
+	if text == "" {
+		return b.writeLine("<rect %s />", attrs)
+	}
+
+	// Write rect with nested text
+	if err := b.writeLine("<rect %s />", attrs); err != nil {
+		return err
+	}
+	// Add text label centered
+	textX := x + width/2
+	textY := y + height/2
+	return b.WriteText(textX, textY, text, "middle", "", "clip-label")
+}
+
+// Attr is an extra attribute written on an element, in addition to its
+// standard ones.
+type Attr struct {
+	Name  string
+	Value string
+}
+
+// WriteRectWithTitle writes a rectangle element containing a <title> child,
+// which browsers display as a tooltip.
+func (b *SVGBuilder) WriteRectWithTitle(x, y, width, height float64, fill, stroke string, id, class, title string) error {
+	return b.WriteRectWithAttrs(x, y, width, height, fill, stroke, id, class, title, nil)
+}
+
+// WriteRectWithAttrs writes a rectangle element like WriteRectWithTitle,
+// followed by the extra attributes in order. Values are escaped.
+func (b *SVGBuilder) WriteRectWithAttrs(x, y, width, height float64, fill, stroke string, id, class, title string, extra []Attr) error {
+	return b.WriteRoundedRect(x, y, width, height, 0, 0, fill, stroke, id, class, title, extra)
+}
+
+// WriteRoundedRect writes a rectangle element like WriteRectWithAttrs, with
+// corners rounded to the radii rx and ry. Radii of 0 give square corners.
+func (b *SVGBuilder) WriteRoundedRect(x, y, width, height, rx, ry float64, fill, stroke string, id, class, title string, extra []Attr) error {
+	var sb strings.Builder
+	writeRectAttrs(&sb, x, y, width, height, fill, stroke, id, class)
+	if rx > 0 {
+		fmt.Fprintf(&sb, ` rx="%.2f"`, rx)
+	}
+	if ry > 0 {
+		fmt.Fprintf(&sb, ` ry="%.2f"`, ry)
+	}
+	for _, attr := range extra {
+		fmt.Fprintf(&sb, ` %s="%s"`, attr.Name, escapeAttr(attr.Value))
+	}
+	return b.writeWithTitle("rect", sb.String(), title)
+}
+
+// rectAttrs formats the attributes of a rectangle element.
+func rectAttrs(x, y, width, height float64, fill, stroke string, id, class string) string {
+	var sb strings.Builder
+	writeRectAttrs(&sb, x, y, width, height, fill, stroke, id, class)
+	return sb.String()
+}
+
+// writeRectAttrs writes the attributes of a rectangle element to sb,
+// avoiding intermediate strings for the many rectangles of large timelines.
+func writeRectAttrs(sb *strings.Builder, x, y, width, height float64, fill, stroke string, id, class string) {
+	width, height = rectSize(width), rectSize(height)
+	fmt.Fprintf(sb, `x="%.2f" y="%.2f" width="%.2f" height="%.2f"`, x, y, width, height)
 	if fill != "" {
-		attrs += fmt.Sprintf(` fill="%s"`, fill)
+		fmt.Fprintf(sb, ` fill="%s"`, escapeAttr(fill))
 	}
 	if stroke != "" {
-		attrs += fmt.Sprintf(` stroke="%s"`, stroke)
+		fmt.Fprintf(sb, ` stroke="%s"`, escapeAttr(stroke))
 	}
 	if id != "" {
-		attrs += fmt.Sprintf(` id="%s"`, escapeAttr(id))
+		fmt.Fprintf(sb, ` id="%s"`, escapeAttr(id))
 	}
 	if class != "" {
-		attrs += fmt.Sprintf(` class="%s"`, escapeAttr(class))
+		fmt.Fprintf(sb, ` class="%s"`, escapeAttr(class))
 	}
+}
 
-	if text == "" {
-		_, err := fmt.Fprintf(b.w, "%s<rect %s />\n", indent(b.indent), attrs)
-		return err
+// rectSize returns a rectangle's width or height, with negative and NaN
+// sizes, which make the rectangle invalid, written as zero.
+func rectSize(v float64) float64 {
+	if !(v > 0) {
+		return 0
 	}
+	return v
+}
 
-	// Write rect with nested text
-	if _, err := fmt.Fprintf(b.w, "%s<rect %s />\n", indent(b.indent), attrs); err != nil {
+// writeWithTitle writes an element with the given attributes and a nested
+// <title>. An empty title writes a self-closing element.
+func (b *SVGBuilder) writeWithTitle(tag, attrs, title string) error {
+	if title == "" {
+		return b.writeLine("<%s %s />", tag, attrs)
+	}
+	if err := b.writeLine("<%s %s>", tag, attrs); err != nil {
 		return err
 	}
-	// Add text label centered
-	textX := x + width/2
-	textY := y + height/2
-	return b.WriteText(textX, textY, text, "middle", "", "clip-label")
+	b.indent++
+	err := b.writeLine("<title>%s</title>", escapeText(title))
+	b.indent--
+	if err != nil {
+		return err
+	}
+	return b.writeLine("</%s>", tag)
 }
 
 // WritePath writes a path element.
 func (b *SVGBuilder) WritePath(d string, fill, stroke string, strokeWidth float64, class string) error {
+	return b.WritePathWithTitle(d, fill, stroke, strokeWidth, "", class, "")
+}
+
+// WritePathWithTitle writes a path element containing a <title> child.
+func (b *SVGBuilder) WritePathWithTitle(d string, fill, stroke string, strokeWidth float64, id, class, title string) error {
 	attrs := fmt.Sprintf(`d="%s"`, d)
 	if fill != "" {
-		attrs += fmt.Sprintf(` fill="%s"`, fill)
+		attrs += fmt.Sprintf(` fill="%s"`, escapeAttr(fill))
 	}
 	if stroke != "" {
-		attrs += fmt.Sprintf(` stroke="%s"`, stroke)
+		attrs += fmt.Sprintf(` stroke="%s"`, escapeAttr(stroke))
 	}
 	if strokeWidth > 0 {
 		attrs += fmt.Sprintf(` stroke-width="%.2f"`, strokeWidth)
 	}
+	if id != "" {
+		attrs += fmt.Sprintf(` id="%s"`, escapeAttr(id))
+	}
 	if class != "" {
 		attrs += fmt.Sprintf(` class="%s"`, escapeAttr(class))
 	}
-	_, err := fmt.Fprintf(b.w, "%s<path %s />\n", indent(b.indent), attrs)
-	return err
+	return b.writeWithTitle("path", attrs, title)
 }
 
 // WriteText writes a text element.
 func (b *SVGBuilder) WriteText(x, y float64, text, anchor, id, class string) error {
+	return b.WriteTextWithFill(x, y, text, anchor, id, class, "")
+}
+
+// WriteTextWithFill writes a text element with an inline fill color, which
+// takes precedence over the fill set by its CSS class.
+func (b *SVGBuilder) WriteTextWithFill(x, y float64, text, anchor, id, class, fill string) error {
+	return b.WriteTextWithAttrs(x, y, text, anchor, id, class, fill, nil)
+}
+
+// WriteTextWithAttrs writes a text element with an inline fill color, if
+// set, and extra attributes.
+func (b *SVGBuilder) WriteTextWithAttrs(x, y float64, text, anchor, id, class, fill string, extra []Attr) error {
 	attrs := fmt.Sprintf(`x="%.2f" y="%.2f"`, x, y)
 	if anchor != "" {
 		attrs += fmt.Sprintf(` text-anchor="%s"`, anchor)
@@ -118,16 +330,50 @@ func (b *SVGBuilder) WriteText(x, y float64, text, anchor, id, class string) err
 	if class != "" {
 		attrs += fmt.Sprintf(` class="%s"`, escapeAttr(class))
 	}
+	if fill != "" {
+		attrs += fmt.Sprintf(` style="fill: %s"`, escapeAttr(fill))
+	}
 	attrs += ` dominant-baseline="middle"`
-	_, err := fmt.Fprintf(b.w, "%s<text %s>%s</text>\n", indent(b.indent), attrs, escapeText(text))
-	return err
+	for _, attr := range extra {
+		attrs += fmt.Sprintf(` %s="%s"`, attr.Name, escapeAttr(attr.Value))
+	}
+	return b.writeLine("<text %s>%s</text>", attrs, escapeText(text))
 }
 
 // WriteLine writes a line element.
 func (b *SVGBuilder) WriteLine(x1, y1, x2, y2 float64, stroke string, strokeWidth float64, class string) error {
 	attrs := fmt.Sprintf(`x1="%.2f" y1="%.2f" x2="%.2f" y2="%.2f"`, x1, y1, x2, y2)
 	if stroke != "" {
-		attrs += fmt.Sprintf(` stroke="%s"`, stroke)
+		attrs += fmt.Sprintf(` stroke="%s"`, escapeAttr(stroke))
+	}
+	if strokeWidth > 0 {
+		attrs += fmt.Sprintf(` stroke-width="%.2f"`, strokeWidth)
+	}
+	if class != "" {
+		attrs += fmt.Sprintf(` class="%s"`, escapeAttr(class))
+	}
+	return b.writeLine("<line %s />", attrs)
+}
+
+// WriteImage writes an image element scaled to cover a rectangle, cropping
+// whatever doesn't fit when clipped to the clip path with the given id.
+func (b *SVGBuilder) WriteImage(href string, x, y, width, height float64, clipID, class string) error {
+	attrs := fmt.Sprintf(`href="%s" x="%.2f" y="%.2f" width="%.2f" height="%.2f" preserveAspectRatio="xMidYMid slice"`, escapeAttr(href), x, y, width, height)
+	if clipID != "" {
+		attrs += fmt.Sprintf(` clip-path="url(#%s)"`, escapeAttr(clipID))
+	}
+	if class != "" {
+		attrs += fmt.Sprintf(` class="%s"`, escapeAttr(class))
+	}
+	return b.writeLine("<image %s />", attrs)
+}
+
+// WritePolyline writes an unfilled polyline element through points, given
+// as space-separated "x,y" pairs.
+func (b *SVGBuilder) WritePolyline(points, stroke string, strokeWidth float64, class string) error {
+	attrs := fmt.Sprintf(`points="%s" fill="none"`, points)
+	if stroke != "" {
+		attrs += fmt.Sprintf(` stroke="%s"`, escapeAttr(stroke))
 	}
 	if strokeWidth > 0 {
 		attrs += fmt.Sprintf(` stroke-width="%.2f"`, strokeWidth)
@@ -135,16 +381,157 @@ func (b *SVGBuilder) WriteLine(x1, y1, x2, y2 float64, stroke string, strokeWidt
 	if class != "" {
 		attrs += fmt.Sprintf(` class="%s"`, escapeAttr(class))
 	}
-	_, err := fmt.Fprintf(b.w, "%s<line %s />\n", indent(b.indent), attrs)
+	return b.writeLine("<polyline %s />", attrs)
+}
+
+// WritePolygon writes a closed polygon element through points, given as
+// space-separated "x,y" pairs.
+func (b *SVGBuilder) WritePolygon(points, fill, stroke string, strokeWidth float64, class string) error {
+	attrs := fmt.Sprintf(`points="%s"`, points)
+	if fill != "" {
+		attrs += fmt.Sprintf(` fill="%s"`, escapeAttr(fill))
+	}
+	if stroke != "" {
+		attrs += fmt.Sprintf(` stroke="%s"`, escapeAttr(stroke))
+	}
+	if strokeWidth > 0 {
+		attrs += fmt.Sprintf(` stroke-width="%.2f"`, strokeWidth)
+	}
+	if class != "" {
+		attrs += fmt.Sprintf(` class="%s"`, escapeAttr(class))
+	}
+	return b.writeLine("<polygon %s />", attrs)
+}
+
+// RectSymbol is a reusable rectangle defined once in <defs> and drawn with
+// WriteUse.
+type RectSymbol struct {
+	ID     string
+	Fill   string
+	Stroke string
+}
+
+// WriteDefs writes a <defs> element defining each symbol as a unit square
+// that stretches to the size of the <use> element drawing it. Strokes keep
+// their width however the symbol is stretched.
+func (b *SVGBuilder) WriteDefs(symbols []RectSymbol) error {
+	if err := b.writeLine("<defs>"); err != nil {
+		return err
+	}
+	b.indent++
+	for _, sym := range symbols {
+		if err := b.writeLine(`<symbol id="%s" viewBox="0 0 1 1" preserveAspectRatio="none">`, escapeAttr(sym.ID)); err != nil {
+			return err
+		}
+		b.indent++
+		err := b.writeLine(`<rect width="1" height="1" fill="%s" stroke="%s" vector-effect="non-scaling-stroke" />`,
+			escapeAttr(sym.Fill), escapeAttr(sym.Stroke))
+		b.indent--
+		if err != nil {
+			return err
+		}
+		if err := b.writeLine("</symbol>"); err != nil {
+			return err
+		}
+	}
+	b.indent--
+	return b.writeLine("</defs>")
+}
+
+// WriteClipRect writes a rectangular clip path, for use with
+// StartClippedGroup.
+func (b *SVGBuilder) WriteClipRect(id string, x, y, width, height float64) error {
+	if err := b.writeLine("<defs>"); err != nil {
+		return err
+	}
+	b.indent++
+	if err := b.writeLine(`<clipPath id="%s">`, escapeAttr(id)); err != nil {
+		return err
+	}
+	b.indent++
+	if err := b.writeLine(`<rect %s />`, rectAttrs(x, y, width, height, "", "", "", "")); err != nil {
+		return err
+	}
+	b.indent--
+	if err := b.writeLine("</clipPath>"); err != nil {
+		return err
+	}
+	b.indent--
+	return b.writeLine("</defs>")
+}
+
+// WriteHatchPattern writes a pattern of diagonal lines in the given color,
+// for use as a fill with fill="url(#id)".
+func (b *SVGBuilder) WriteHatchPattern(id, color string) error {
+	if err := b.writeLine("<defs>"); err != nil {
+		return err
+	}
+	b.indent++
+	if err := b.writeLine(`<pattern id="%s" patternUnits="userSpaceOnUse" width="6" height="6" patternTransform="rotate(45)">`, escapeAttr(id)); err != nil {
+		return err
+	}
+	b.indent++
+	if err := b.writeLine(`<line x1="0" y1="0" x2="0" y2="6" stroke="%s" stroke-width="2" />`, escapeAttr(color)); err != nil {
+		return err
+	}
+	b.indent--
+	if err := b.writeLine("</pattern>"); err != nil {
+		return err
+	}
+	b.indent--
+	return b.writeLine("</defs>")
+}
+
+// WriteUse writes a <use> element drawing the symbol with the given id in a
+// rectangle, with an optional <title> child.
+func (b *SVGBuilder) WriteUse(symbolID string, x, y, width, height float64, id, class, title string) error {
+	attrs := fmt.Sprintf(`href="#%s" x="%.2f" y="%.2f" width="%.2f" height="%.2f"`, escapeAttr(symbolID), x, y, width, height)
+	if id != "" {
+		attrs += fmt.Sprintf(` id="%s"`, escapeAttr(id))
+	}
+	if class != "" {
+		attrs += fmt.Sprintf(` class="%s"`, escapeAttr(class))
+	}
+	return b.writeWithTitle("use", attrs, title)
+}
+
+// WriteRaw writes markup as is, such as a complete SVG document to nest
+// inside this one.
+func (b *SVGBuilder) WriteRaw(markup []byte) error {
+	_, err := b.w.Write(markup)
+	return err
+}
+
+// WriteScript writes a script element, with the script in a CDATA section
+// so it needs no escaping.
+func (b *SVGBuilder) WriteScript(script string) error {
+	if b.compact {
+		return b.writeLine("<script><![CDATA[%s]]></script>", strings.Join(strings.Fields(script), " "))
+	}
+	_, err := fmt.Fprintf(b.w, "%s<script><![CDATA[\n%s\n%s]]></script>\n", indent(b.indent), script, indent(b.indent))
 	return err
 }
 
 // WriteStyle writes a style element with CSS.
 func (b *SVGBuilder) WriteStyle(css string) error {
+	if b.compact {
+		return b.writeLine("<style>%s</style>", strings.Join(strings.Fields(css), " "))
+	}
 	_, err := fmt.Fprintf(b.w, "%s<style>\n%s\n%s</style>\n", indent(b.indent), css, indent(b.indent))
 	return err
 }
 
+// writeLine writes one element, or one tag of an element with children, on
+// its own line at the current indentation. In compact mode the indentation
+// and line break are omitted.
+func (b *SVGBuilder) writeLine(format string, args ...any) error {
+	if !b.compact {
+		format = indent(b.indent) + format + "\n"
+	}
+	_, err := fmt.Fprintf(b.w, format, args...)
+	return err
+}
+
 // indent creates an indentation string.
 func indent(level int) string {
 	return strings.Repeat("  ", level)
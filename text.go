@@ -0,0 +1,35 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Contributors to the OpenTimelineIO project
+
+package svg
+
+import "unicode/utf8"
+
+// averageGlyphWidth is the average glyph width of a sans-serif font as a
+// fraction of its font size.
+const averageGlyphWidth = 0.6
+
+// ellipsis is appended to truncated labels.
+const ellipsis = "…"
+
+// estimateTextWidth estimates the rendered width of text in pixels using an
+// average glyph width heuristic.
+func estimateTextWidth(text string, fontSize float64) float64 {
+	return float64(utf8.RuneCountInString(text)) * fontSize * averageGlyphWidth
+}
+
+// truncateText shortens text with a trailing ellipsis so that its estimated
+// width fits within maxWidth. It returns "" if not even the ellipsis fits.
+func truncateText(text string, maxWidth, fontSize float64) string {
+	if estimateTextWidth(text, fontSize) <= maxWidth {
+		return text
+	}
+	runes := []rune(text)
+	for n := len(runes) - 1; n >= 0; n-- {
+		truncated := string(runes[:n]) + ellipsis
+		if estimateTextWidth(truncated, fontSize) <= maxWidth {
+			return truncated
+		}
+	}
+	return ""
+}
@@ -0,0 +1,38 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Contributors to the OpenTimelineIO project
+
+package svg
+
+import "testing"
+
+func TestEstimateTextWidth(t *testing.T) {
+	if w := estimateTextWidth("abcde", 10); w != 30 {
+		t.Errorf("estimateTextWidth(abcde, 10) = %.2f, want 30", w)
+	}
+
+	if w := estimateTextWidth("äöü", 10); w != 18 {
+		t.Errorf("estimateTextWidth should count runes, got %.2f", w)
+	}
+}
+
+func TestTruncateText(t *testing.T) {
+	tests := []struct {
+		text     string
+		maxWidth float64
+		expected string
+	}{
+		{"Short", 100, "Short"},
+		{"A very long clip name", 60, "A very lo…"},
+		{"A very long clip name", 12, "A…"},
+		{"A very long clip name", 6, "…"},
+		{"A very long clip name", 5, ""},
+		{"", 0, ""},
+	}
+
+	for _, tt := range tests {
+		result := truncateText(tt.text, tt.maxWidth, 10)
+		if result != tt.expected {
+			t.Errorf("truncateText(%q, %.0f) = %q, want %q", tt.text, tt.maxWidth, result, tt.expected)
+		}
+	}
+}
@@ -0,0 +1,124 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Contributors to the OpenTimelineIO project
+
+package svg
+
+import "math"
+
+// Theme holds the colors used to render a timeline, and optionally heavier
+// borders and larger text for legibility.
+type Theme struct {
+	VideoTrack   string
+	AudioTrack   string
+	Gap          string
+	Transition   string
+	Background   string
+	Grid         string
+	Text         string
+	RulerText    string
+	TrackLabelBg string
+	ClipText     string
+	ClipBorder   string
+	GapBorder    string
+	GapText      string
+	Playhead     string
+	Highlight    string
+	MissingMedia string
+
+	// StrokeWidth is the width of clip and gap borders in pixels. Missing
+	// media outlines and transitions are drawn twice as wide. Zero uses 1.
+	StrokeWidth float64
+	// FontScale scales all font sizes, including those set with
+	// SetFontSizes. Zero uses 1.
+	FontScale float64
+	// BoldLabels draws clip labels in bold.
+	BoldLabels bool
+}
+
+// DefaultTheme returns the default light color scheme.
+func DefaultTheme() Theme {
+	return Theme{
+		VideoTrack:   VideoTrackColor,
+		AudioTrack:   AudioTrackColor,
+		Gap:          GapColor,
+		Transition:   TransitionColor,
+		Background:   BackgroundColor,
+		Grid:         GridColor,
+		Text:         TextColor,
+		RulerText:    RulerTextColor,
+		TrackLabelBg: TrackLabelBg,
+		ClipText:     ClipTextColor,
+		ClipBorder:   ClipBorderColor,
+		GapBorder:    GapBorderColor,
+		GapText:      GapTextColor,
+		Playhead:     PlayheadColor,
+		Highlight:    HighlightColor,
+		MissingMedia: MissingMediaColor,
+	}
+}
+
+// DarkTheme returns a color scheme suited to dark backgrounds.
+func DarkTheme() Theme {
+	return Theme{
+		VideoTrack:   "#3A6EA5",
+		AudioTrack:   "#3E8E63",
+		Gap:          "#3A3A3A",
+		Transition:   "#E0A040",
+		Background:   "#1E1E1E",
+		Grid:         "#444444",
+		Text:         "#E0E0E0",
+		RulerText:    "#AAAAAA",
+		TrackLabelBg: "#2A2A2A",
+		ClipText:     "#F0F0F0",
+		ClipBorder:   "#111111",
+		GapBorder:    "#666666",
+		GapText:      "#BBBBBB",
+		Playhead:     "#FF5252",
+		Highlight:    "#FFCA28",
+		MissingMedia: "#FF5252",
+	}
+}
+
+// HighContrastTheme returns a color scheme for low-vision viewers: white
+// text and borders on black, with saturated track colors that keep white
+// labels above the WCAG AAA contrast ratio, wider borders, and larger bold
+// text.
+func HighContrastTheme() Theme {
+	return Theme{
+		VideoTrack:   "#0039A6",
+		AudioTrack:   "#005A24",
+		Gap:          "#000000",
+		Transition:   "#FFD700",
+		Background:   "#000000",
+		Grid:         "#FFFFFF",
+		Text:         "#FFFFFF",
+		RulerText:    "#FFFFFF",
+		TrackLabelBg: "#000000",
+		ClipText:     "#FFFFFF",
+		ClipBorder:   "#FFFFFF",
+		GapBorder:    "#FFFFFF",
+		GapText:      "#FFFFFF",
+		Playhead:     "#FF4D4D",
+		Highlight:    "#FFD700",
+		MissingMedia: "#FF4D4D",
+		StrokeWidth:  2,
+		FontScale:    1.25,
+		BoldLabels:   true,
+	}
+}
+
+// strokeWidth returns the theme's border width, defaulting to 1.
+func (t Theme) strokeWidth() float64 {
+	if t.StrokeWidth > 0 {
+		return t.StrokeWidth
+	}
+	return 1
+}
+
+// fontSize returns a font size scaled by the theme's font scale.
+func (t Theme) fontSize(size int) int {
+	if t.FontScale <= 0 {
+		return size
+	}
+	return max(int(math.Round(float64(size)*t.FontScale)), 1)
+}
@@ -0,0 +1,119 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Contributors to the OpenTimelineIO project
+
+package svg
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDefaultTheme(t *testing.T) {
+	theme := DefaultTheme()
+
+	if theme.VideoTrack != VideoTrackColor {
+		t.Errorf("Expected video color %s, got %s", VideoTrackColor, theme.VideoTrack)
+	}
+
+	if theme.AudioTrack != AudioTrackColor {
+		t.Errorf("Expected audio color %s, got %s", AudioTrackColor, theme.AudioTrack)
+	}
+
+	if theme.Gap != GapColor {
+		t.Errorf("Expected gap color %s, got %s", GapColor, theme.Gap)
+	}
+}
+
+func TestSetTheme(t *testing.T) {
+	theme := DefaultTheme()
+	theme.VideoTrack = "#123456"
+	theme.Grid = "#ABCDEF"
+
+	svg := encodeString(t, buildSimpleTimeline(t), func(enc *Encoder) {
+		enc.SetTheme(theme)
+	})
+
+	if !strings.Contains(svg, `fill="#123456"`) {
+		t.Error("SVG missing themed video track color")
+	}
+
+	if !strings.Contains(svg, `stroke="#ABCDEF"`) {
+		t.Error("SVG missing themed grid color")
+	}
+
+	if strings.Contains(svg, VideoTrackColor) {
+		t.Error("SVG still uses default video track color")
+	}
+}
+
+func TestDarkTheme(t *testing.T) {
+	dark := DarkTheme()
+
+	svg := encodeString(t, buildSimpleTimeline(t), func(enc *Encoder) {
+		enc.SetTheme(dark)
+	})
+
+	if !strings.Contains(svg, "fill: "+dark.Text+";") {
+		t.Error("CSS missing dark track label color")
+	}
+
+	if !strings.Contains(svg, "fill: "+dark.ClipText+";") {
+		t.Error("CSS missing dark clip label color")
+	}
+
+	if strings.Contains(svg, "#333") || strings.Contains(svg, "white") {
+		t.Error("SVG still contains light theme colors")
+	}
+}
+
+func TestHighContrastTheme(t *testing.T) {
+	theme := HighContrastTheme()
+
+	// White clip labels must reach the WCAG AAA ratio of 7:1 on each track
+	for _, color := range []string{theme.VideoTrack, theme.AudioTrack} {
+		r, g, b, ok := parseHexColor(color)
+		if !ok {
+			t.Fatalf("Invalid track color %s", color)
+		}
+		if ratio := 1.05 / (relativeLuminance(r, g, b) + 0.05); ratio < 7 {
+			t.Errorf("Track color %s has contrast ratio %.2f with white, want at least 7", color, ratio)
+		}
+		if contrastColor(color) != theme.ClipText {
+			t.Errorf("Clip labels on %s should be %s", color, theme.ClipText)
+		}
+	}
+
+	plain := encodeString(t, buildSimpleTimeline(t), nil)
+	svg := encodeString(t, buildSimpleTimeline(t), func(enc *Encoder) {
+		enc.SetTheme(theme)
+	})
+	if !strings.Contains(svg, ".clip {\n      stroke: #FFFFFF;\n      stroke-width: 2;") {
+		t.Error("CSS missing wider clip borders")
+	}
+	if !strings.Contains(svg, "stroke-width: 4;\n      fill: none;") {
+		t.Error("CSS missing wider transitions")
+	}
+	if !strings.Contains(svg, ".track-label {\n      font-size: 15px;") || !strings.Contains(svg, ".clip-label {\n      font-size: 13px;") {
+		t.Error("CSS missing scaled font sizes")
+	}
+	for _, want := range []string{
+		".timeline-title {\n      font-size: 23px;",
+		".transition-label {\n      font-size: 13px;",
+		".legend-text {\n      font-size: 13px;",
+		".footer-text {\n      font-size: 11px;",
+		".empty-message {\n      font-size: 18px;",
+	} {
+		if !strings.Contains(svg, want) {
+			t.Errorf("CSS missing scaled size %q", want)
+		}
+	}
+	if !strings.Contains(plain, ".timeline-title {\n      font-size: 18px;") {
+		t.Error("Default theme should keep the title size")
+	}
+	if !strings.Contains(svg, ".clip-label {\n      font-weight: bold;") {
+		t.Error("CSS missing bold clip labels")
+	}
+	if strings.Contains(plain, "stroke-width: 2;\n      stroke-dasharray: 2,2") || strings.Contains(plain, ".clip-label {\n      font-weight: bold;") {
+		t.Error("Default theme should keep thin borders and regular labels")
+	}
+}
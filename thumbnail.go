@@ -0,0 +1,31 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Contributors to the OpenTimelineIO project
+
+package svg
+
+import (
+	"fmt"
+
+	"github.com/Avalanche-io/gotio"
+)
+
+// ThumbnailProvider supplies a representative frame for video clips.
+type ThumbnailProvider interface {
+	// Thumbnail returns the URL of an image for a clip, which may be a data
+	// URI, or false if it has no image for the clip.
+	Thumbnail(clip *gotio.Clip) (string, bool)
+}
+
+// drawThumbnail draws the thumbnail of a clip filling its rectangle, cropped
+// to fit, if the thumbnail provider has one.
+func (e *Encoder) drawThumbnail(builder *SVGBuilder, clip *gotio.Clip, x, y, width, height float64) error {
+	href, ok := e.thumbnailProvider.Thumbnail(clip)
+	if !ok || href == "" || width <= 0 || height <= 0 {
+		return nil
+	}
+	clipID := builder.UniqueID(fmt.Sprintf("thumbnail-%s", sanitizeID(clip.Name())))
+	if err := builder.WriteClipRect(clipID, x, y, width, height); err != nil {
+		return err
+	}
+	return builder.WriteImage(href, x, y, width, height, clipID, "thumbnail")
+}
@@ -0,0 +1,69 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Contributors to the OpenTimelineIO project
+
+package svg
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/Avalanche-io/gotio"
+)
+
+// thumbnailsByName provides thumbnails for clips by name.
+type thumbnailsByName map[string]string
+
+func (p thumbnailsByName) Thumbnail(clip *gotio.Clip) (string, bool) {
+	href, ok := p[clip.Name()]
+	return href, ok
+}
+
+func TestThumbnailProvider(t *testing.T) {
+	timeline := gotio.NewTimeline("Storyboard", nil, nil)
+	video := gotio.NewTrack("V1", nil, gotio.TrackKindVideo, nil, nil)
+	audio := gotio.NewTrack("A1", nil, gotio.TrackKindAudio, nil, nil)
+	for _, name := range []string{"Shot 1", "Shot 2"} {
+		if err := video.AppendChild(newTestClip(name, 48, 24)); err != nil {
+			t.Fatalf("Failed to append clip: %v", err)
+		}
+	}
+	if err := audio.AppendChild(newTestClip("Sound", 96, 24)); err != nil {
+		t.Fatalf("Failed to append clip: %v", err)
+	}
+	for _, track := range []*gotio.Track{video, audio} {
+		if err := timeline.Tracks().AppendChild(track); err != nil {
+			t.Fatalf("Failed to append track: %v", err)
+		}
+	}
+
+	plain := encodeString(t, timeline, nil)
+	svg := encodeString(t, timeline, func(enc *Encoder) {
+		enc.SetThumbnailProvider(thumbnailsByName{
+			"Shot 1": "data:image/png;base64,AAAA",
+			"Sound":  "sound.png",
+		})
+	})
+
+	if n := strings.Count(svg, "<image "); n != 1 {
+		t.Fatalf("Expected a thumbnail on the one video clip provided, found %d", n)
+	}
+	want := `<image href="data:image/png;base64,AAAA" x="100.00" y="102.00" width="530.00" height="76.00" preserveAspectRatio="xMidYMid slice" clip-path="url(#thumbnail-Shot_1)" class="thumbnail" />`
+	if !strings.Contains(svg, want) {
+		t.Errorf("Thumbnail not filling the clip, want %s", want)
+	}
+	if !strings.Contains(svg, `<clipPath id="thumbnail-Shot_1">`) {
+		t.Error("Thumbnail should be clipped to the clip")
+	}
+
+	// The label is drawn over the thumbnail
+	if strings.Index(svg, "<image ") > strings.Index(svg, ">Shot 1</text>") {
+		t.Error("Thumbnail should be drawn behind the clip label")
+	}
+
+	svg = encodeString(t, timeline, func(enc *Encoder) {
+		enc.SetThumbnailProvider(thumbnailsByName{})
+	})
+	if svg != plain {
+		t.Error("Clips without thumbnails should be drawn as before")
+	}
+}
@@ -0,0 +1,169 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Contributors to the OpenTimelineIO project
+
+package svg
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/Avalanche-io/gotio"
+	"github.com/Avalanche-io/gotio/opentime"
+)
+
+// TimeFormat selects how times are labeled on the ruler.
+type TimeFormat int
+
+const (
+	// TimeFormatSeconds labels times as seconds, minutes:seconds, or
+	// hours:minutes:seconds.
+	TimeFormatSeconds TimeFormat = iota
+	// TimeFormatTimecode labels times as SMPTE timecode (HH:MM:SS:FF).
+	TimeFormatTimecode
+	// TimeFormatFrames labels times as frame numbers.
+	TimeFormatFrames
+	// TimeFormatMilliseconds labels times under a second in milliseconds,
+	// e.g. "500ms", and longer times like TimeFormatSeconds.
+	TimeFormatMilliseconds
+)
+
+// formatTimeAs formats seconds in the given format at the given frame rate.
+func formatTimeAs(seconds float64, format TimeFormat, rate float64) string {
+	switch format {
+	case TimeFormatTimecode:
+		return formatTimecode(secondsToFrames(seconds, rate), rate)
+	case TimeFormatFrames:
+		return fmt.Sprintf("%d", secondsToFrames(seconds, rate))
+	case TimeFormatMilliseconds:
+		return formatMilliseconds(seconds)
+	default:
+		return formatTime(seconds)
+	}
+}
+
+// tickLabel labels the ruler tick at the given time in seconds from the
+// start of the ruler: with the time label function if set, and otherwise in
+// the given format. Either is given the absolute time, offset by the
+// timeline's global start time.
+func (e *Encoder) tickLabel(l *layout, time float64, format TimeFormat) string {
+	seconds := l.startSeconds + time
+	if e.timeLabelFunc == nil {
+		return formatTimeAs(seconds, format, l.rate)
+	}
+	rate := l.rate
+	if rate <= 0 {
+		rate = 1
+	}
+	// Ticks on frame boundaries get whole frame values, free of rounding
+	// error from the conversion to seconds
+	value := seconds * rate
+	if whole := math.Round(value); math.Abs(value-whole) < 1e-6 {
+		value = whole
+	}
+	return e.timeLabelFunc(opentime.NewRationalTime(value, rate))
+}
+
+// formatMilliseconds formats seconds as whole milliseconds, e.g. "42ms",
+// if under a second, and like formatTime otherwise.
+func formatMilliseconds(seconds float64) string {
+	if ms := int(math.Round(seconds * 1000)); ms > -1000 && ms < 1000 {
+		return fmt.Sprintf("%dms", ms)
+	}
+	return formatTime(seconds)
+}
+
+// timelineRate returns the frame rate used for labeling a timeline: the
+// rate of its global start time, falling back to the rate of its duration.
+func timelineRate(t *gotio.Timeline, duration opentime.RationalTime) float64 {
+	if start := t.GlobalStartTime(); start != nil && start.Rate() > 0 {
+		return start.Rate()
+	}
+	return duration.Rate()
+}
+
+// globalStartSeconds returns the timeline's global start time in seconds,
+// or zero if it has none.
+func globalStartSeconds(t *gotio.Timeline) float64 {
+	if start := t.GlobalStartTime(); start != nil {
+		return start.ToSeconds()
+	}
+	return 0
+}
+
+// secondsToFrames converts seconds to a whole frame count at rate.
+func secondsToFrames(seconds, rate float64) int {
+	return int(math.Round(seconds * rate))
+}
+
+// isDropFrameRate reports whether rate is an NTSC rate that uses drop-frame
+// timecode (29.97 or 59.94).
+func isDropFrameRate(rate float64) bool {
+	return math.Abs(rate-30000.0/1001.0) < 0.01 || math.Abs(rate-60000.0/1001.0) < 0.01
+}
+
+// formatTimecode formats a frame count as SMPTE timecode. Fractional rates
+// count frames at the nearest whole rate; 29.97 and 59.94 use drop-frame
+// numbering with a semicolon before the frame field.
+func formatTimecode(frames int, rate float64) string {
+	nominal := int(math.Round(rate))
+	if nominal <= 0 {
+		return ""
+	}
+
+	sign := ""
+	if frames < 0 {
+		sign = "-"
+		frames = -frames
+	}
+
+	sep := ":"
+	if isDropFrameRate(rate) {
+		sep = ";"
+		// Skip frame numbers 0 and 1 (0-3 at 59.94) at the start of every
+		// minute except each tenth minute.
+		drop := nominal / 15
+		framesPerMinute := nominal*60 - drop
+		framesPer10Minutes := framesPerMinute*10 + drop
+
+		tens := frames / framesPer10Minutes
+		rem := frames % framesPer10Minutes
+		frames += drop * 9 * tens
+		if rem > drop {
+			frames += drop * ((rem - drop) / framesPerMinute)
+		}
+	}
+
+	ff := frames % nominal
+	ss := (frames / nominal) % 60
+	mm := (frames / (nominal * 60)) % 60
+	hh := frames / (nominal * 3600)
+	return fmt.Sprintf("%s%02d:%02d:%02d%s%02d", sign, hh, mm, ss, sep, ff)
+}
+
+// calculateFrameInterval calculates a whole-frame interval for ruler marks,
+// giving about targetMarks marks over the duration. Intervals of a second or
+// more are multiples of the nominal frame rate.
+func calculateFrameInterval(durationFrames, rate, targetMarks float64) int {
+	nominal := int(math.Round(rate))
+	if nominal <= 0 {
+		nominal = 1
+	}
+
+	var intervals []int
+	for _, n := range []int{1, 2, 5, 10} {
+		if n < nominal {
+			intervals = append(intervals, n)
+		}
+	}
+	for _, secs := range []int{1, 2, 5, 10, 15, 30, 60, 120, 300, 600, 1800, 3600, 7200, 10800, 21600, 43200, 86400} {
+		intervals = append(intervals, secs*nominal)
+	}
+
+	idealInterval := durationFrames / targetMarks
+	for _, interval := range intervals {
+		if float64(interval) >= idealInterval {
+			return interval
+		}
+	}
+	return intervals[len(intervals)-1]
+}
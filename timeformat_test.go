@@ -0,0 +1,279 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Contributors to the OpenTimelineIO project
+
+package svg
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/Avalanche-io/gotio"
+	"github.com/Avalanche-io/gotio/opentime"
+)
+
+func TestFormatTimecode(t *testing.T) {
+	tests := []struct {
+		frames   int
+		rate     float64
+		expected string
+	}{
+		{0, 24, "00:00:00:00"},
+		{23, 24, "00:00:00:23"},
+		{24, 24, "00:00:01:00"},
+		{86400, 24, "01:00:00:00"},
+		{24, 24000.0 / 1001.0, "00:00:01:00"},
+		{25 * 61, 25, "00:01:01:00"},
+		{-24, 24, "-00:00:01:00"},
+		{1799, 30000.0 / 1001.0, "00:00:59;29"},
+		{1800, 30000.0 / 1001.0, "00:01:00;02"},
+		{17982, 30000.0 / 1001.0, "00:10:00;00"},
+
+		// Drop-frame reference values: frames ;00 and ;01 are skipped at
+		// each minute but the tenth, and ;00 to ;03 at 59.94
+		{1, 30000.0 / 1001.0, "00:00:00;01"},
+		{3597, 30000.0 / 1001.0, "00:01:59;29"},
+		{3598, 30000.0 / 1001.0, "00:02:00;02"},
+		{17981, 30000.0 / 1001.0, "00:09:59;29"},
+		{17983, 30000.0 / 1001.0, "00:10:00;01"},
+		{17984, 30000.0 / 1001.0, "00:10:00;02"},
+		{19782, 30000.0 / 1001.0, "00:11:00;02"},
+		{107892, 30000.0 / 1001.0, "01:00:00;00"},
+		{2589407, 30000.0 / 1001.0, "23:59:59;29"},
+		{-1800, 30000.0 / 1001.0, "-00:01:00;02"},
+		{3599, 60000.0 / 1001.0, "00:00:59;59"},
+		{3600, 60000.0 / 1001.0, "00:01:00;04"},
+		{35964, 60000.0 / 1001.0, "00:10:00;00"},
+		{215784, 60000.0 / 1001.0, "01:00:00;00"},
+	}
+
+	for _, tt := range tests {
+		result := formatTimecode(tt.frames, tt.rate)
+		if result != tt.expected {
+			t.Errorf("formatTimecode(%d, %.3f) = %s, want %s", tt.frames, tt.rate, result, tt.expected)
+		}
+	}
+}
+
+func TestFormatTimeAs(t *testing.T) {
+	tests := []struct {
+		seconds  float64
+		format   TimeFormat
+		expected string
+	}{
+		{0.5, TimeFormatSeconds, "0.5s"},
+		{0.5, TimeFormatFrames, "12"},
+		{0.5, TimeFormatMilliseconds, "500ms"},
+		{0.042, TimeFormatMilliseconds, "42ms"},
+		{1.0 / 24, TimeFormatMilliseconds, "42ms"},
+		{0.005, TimeFormatMilliseconds, "5ms"},
+		{0.0001, TimeFormatMilliseconds, "0ms"},
+		{-0.25, TimeFormatMilliseconds, "-250ms"},
+		{0.9999, TimeFormatMilliseconds, "1.0s"},
+		{1.5, TimeFormatMilliseconds, "1.5s"},
+		{90, TimeFormatMilliseconds, "1:30"},
+	}
+
+	for _, tt := range tests {
+		if result := formatTimeAs(tt.seconds, tt.format, 24); result != tt.expected {
+			t.Errorf("formatTimeAs(%v, %v) = %s, want %s", tt.seconds, tt.format, result, tt.expected)
+		}
+	}
+}
+
+func TestMillisecondsRuler(t *testing.T) {
+	timeline := gotio.NewTimeline("Short", nil, nil)
+	track := gotio.NewTrack("V1", nil, gotio.TrackKindVideo, nil, nil)
+	if err := track.AppendChild(newTestClip("Blink", 12, 24)); err != nil {
+		t.Fatalf("Failed to append clip: %v", err)
+	}
+	if err := timeline.Tracks().AppendChild(track); err != nil {
+		t.Fatalf("Failed to append track: %v", err)
+	}
+
+	svg := encodeString(t, timeline, func(enc *Encoder) {
+		enc.SetTimeFormat(TimeFormatMilliseconds)
+	})
+
+	// Half a second is marked every 50ms
+	for _, label := range []string{">0ms</text>", ">50ms</text>", ">500ms</text>"} {
+		if !strings.Contains(svg, label) {
+			t.Errorf("Ruler missing label %s", label)
+		}
+	}
+}
+
+func TestTimecodeRuler(t *testing.T) {
+	svg := encodeString(t, buildSimpleTimeline(t), func(enc *Encoder) {
+		enc.SetTimeFormat(TimeFormatTimecode)
+	})
+
+	if !strings.Contains(svg, ">00:00:01:00</text>") {
+		t.Error("Ruler missing timecode label")
+	}
+
+	if strings.Contains(svg, ">1.0s</text>") {
+		t.Error("Ruler still uses seconds labels")
+	}
+}
+
+func TestDropFrameRuler(t *testing.T) {
+	const rate = 30000.0 / 1001.0
+	timeline := gotio.NewTimeline("Broadcast", nil, nil)
+	track := gotio.NewTrack("V1", nil, gotio.TrackKindVideo, nil, nil)
+	if err := track.AppendChild(newTestClip("Program", 3600, rate)); err != nil {
+		t.Fatalf("Failed to append clip: %v", err)
+	}
+	if err := timeline.Tracks().AppendChild(track); err != nil {
+		t.Fatalf("Failed to append track: %v", err)
+	}
+
+	svg := encodeString(t, timeline, func(enc *Encoder) {
+		enc.SetTimeFormat(TimeFormatTimecode)
+	})
+
+	// Ticks land on whole frames every 300 frames, so the one-minute tick
+	// at frame 1800 is labeled past the two dropped frame numbers
+	for _, label := range []string{">00:00:00;00</text>", ">00:00:10;00</text>", ">00:01:00;02</text>", ">00:01:10;02</text>"} {
+		if !strings.Contains(svg, label) {
+			t.Errorf("Ruler missing label %s", label)
+		}
+	}
+	if strings.Contains(svg, ">00:00:59;") {
+		t.Error("Ruler tick fell between frames")
+	}
+	if strings.Contains(svg, ">00:00:10:00</text>") {
+		t.Error("Drop-frame labels should use a semicolon before the frame field")
+	}
+}
+
+func TestCalculateFrameInterval(t *testing.T) {
+	tests := []struct {
+		durationFrames float64
+		rate           float64
+		expected       int
+	}{
+		{12, 24, 1},
+		{100, 24, 10},
+		{240, 24, 24},
+		{480, 24, 48},
+		{1440, 24, 120},
+		{300, 25, 25},
+		{24 * 4 * 3600, 24, 24 * 1800},
+		{24 * 48 * 3600, 24, 24 * 21600},
+	}
+
+	for _, tt := range tests {
+		result := calculateFrameInterval(tt.durationFrames, tt.rate, DefaultRulerTargetMarks)
+		if result != tt.expected {
+			t.Errorf("calculateFrameInterval(%.0f, %.0f) = %d, want %d", tt.durationFrames, tt.rate, result, tt.expected)
+		}
+	}
+}
+
+func TestFramesRuler(t *testing.T) {
+	svg := encodeString(t, buildSimpleTimeline(t), func(enc *Encoder) {
+		enc.SetTimeFormat(TimeFormatFrames)
+	})
+
+	for _, label := range []string{">0</text>", ">24</text>", ">240</text>"} {
+		if !strings.Contains(svg, label) {
+			t.Errorf("Ruler missing frame label %s", label)
+		}
+	}
+
+	for _, line := range strings.Split(svg, "\n") {
+		if !strings.Contains(line, `class="ruler-text"`) {
+			continue
+		}
+		label := line[strings.Index(line, ">")+1 : strings.LastIndex(line, "<")]
+		if strings.Contains(label, ".") {
+			t.Errorf("Ruler has fractional frame label %q", label)
+		}
+	}
+}
+
+func TestGlobalStartTimeRuler(t *testing.T) {
+	start := opentime.NewRationalTime(86400, 24) // 01:00:00:00
+	timeline := gotio.NewTimeline("Broadcast", &start, nil)
+	track := gotio.NewTrack("V1", nil, gotio.TrackKindVideo, nil, nil)
+	if err := track.AppendChild(newTestClip("Program", 240, 24)); err != nil {
+		t.Fatalf("Failed to append clip: %v", err)
+	}
+	if err := timeline.Tracks().AppendChild(track); err != nil {
+		t.Fatalf("Failed to append track: %v", err)
+	}
+
+	svg := encodeString(t, timeline, func(enc *Encoder) {
+		enc.SetTimeFormat(TimeFormatTimecode)
+	})
+
+	if !strings.Contains(svg, fmt.Sprintf(`<text x="%d.00" y="%d.00" text-anchor="middle" class="ruler-text" dominant-baseline="middle">01:00:00:00</text>`, MarginLeft, MarginTop+RulerHeight/2)) {
+		t.Error("First ruler label is not offset by the global start time")
+	}
+
+	if !strings.Contains(svg, ">01:00:10:00</text>") {
+		t.Error("Last ruler label is not offset by the global start time")
+	}
+}
+
+func TestTimeLabelFunc(t *testing.T) {
+	start := opentime.NewRationalTime(86400, 24) // 01:00:00:00
+	timeline := gotio.NewTimeline("Music", &start, nil)
+	track := gotio.NewTrack("V1", nil, gotio.TrackKindVideo, nil, nil)
+	if err := track.AppendChild(newTestClip("Cue", 240, 24)); err != nil {
+		t.Fatalf("Failed to append clip: %v", err)
+	}
+	if err := timeline.Tracks().AppendChild(track); err != nil {
+		t.Fatalf("Failed to append track: %v", err)
+	}
+
+	var times []opentime.RationalTime
+	label := func(time opentime.RationalTime) string {
+		times = append(times, time)
+		return fmt.Sprintf("F%g@%g", time.Value(), time.Rate())
+	}
+
+	svg := encodeString(t, timeline, func(enc *Encoder) {
+		enc.SetTimeFormat(TimeFormatTimecode)
+		enc.SetTimeLabelFunc(label)
+	})
+	if len(times) == 0 {
+		t.Fatal("Time label function not called")
+	}
+	if !strings.Contains(svg, `class="ruler-text" dominant-baseline="middle">F86400@24</text>`) ||
+		!strings.Contains(svg, `class="ruler-text" dominant-baseline="middle">F86640@24</text>`) {
+		t.Error("Ruler labels should come from the function, given absolute times")
+	}
+	if strings.Contains(svg, ">01:00:") {
+		t.Error("Time format should not be used with a time label function")
+	}
+	if n := strings.Count(svg, `class="ruler-text" dominant-baseline="middle">F`); n != len(times) {
+		t.Errorf("Expected %d labels from the function, found %d", len(times), n)
+	}
+
+	// A dual ruler keeps elapsed seconds on its bottom row
+	svg = encodeString(t, timeline, func(enc *Encoder) {
+		enc.SetDualRuler(true)
+		enc.SetTimeLabelFunc(label)
+	})
+	if !strings.Contains(svg, ">F86424@24</text>") || !strings.Contains(svg, ">1.0s</text>") {
+		t.Error("Dual ruler should label its top row with the function")
+	}
+
+	svg = encodeString(t, timeline, func(enc *Encoder) {
+		enc.SetOrientation(OrientationVertical)
+		enc.SetTimeLabelFunc(label)
+	})
+	if !strings.Contains(svg, ">F86400@24</text>") {
+		t.Error("Vertical ruler should label ticks with the function")
+	}
+
+	svg = encodeString(t, timeline, func(enc *Encoder) {
+		enc.SetTimeLabelFunc(label)
+		enc.SetTimeLabelFunc(nil)
+	})
+	if strings.Contains(svg, ">F86400@24</text>") || !strings.Contains(svg, ">1:00:00</text>") {
+		t.Error("A nil function should restore the time format")
+	}
+}
@@ -0,0 +1,80 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Contributors to the OpenTimelineIO project
+
+package svg
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/Avalanche-io/gotio"
+)
+
+const (
+	// trackStateIconSize is the width and height of a mute or lock icon.
+	trackStateIconSize = 10.0
+	// trackStateIconGap is the space between a track's label and its
+	// icons, and between the icons.
+	trackStateIconGap = 4.0
+)
+
+// metadataFlag reports whether metadata sets key to true, as a bool or a
+// string such as "true" or "1".
+func metadataFlag(metadata map[string]any, key string) bool {
+	if key == "" {
+		return false
+	}
+	switch v := metadata[key].(type) {
+	case bool:
+		return v
+	case string:
+		b, err := strconv.ParseBool(v)
+		return err == nil && b
+	default:
+		return false
+	}
+}
+
+// trackState returns whether a track's metadata marks it muted or locked.
+func (e *Encoder) trackState(track *gotio.Track) (muted, locked bool) {
+	md := track.Metadata()
+	return metadataFlag(md, e.muteKey), metadataFlag(md, e.lockKey)
+}
+
+// muteIconPath returns the SVG path data for a crossed-out speaker whose
+// top-left corner is at x, y.
+func muteIconPath(x, y float64) string {
+	s := trackStateIconSize
+	return fmt.Sprintf("M %.2f %.2f h %.2f l %.2f %.2f v %.2f l %.2f %.2f h %.2f Z M %.2f %.2f l %.2f %.2f M %.2f %.2f l %.2f %.2f",
+		x, y+s*0.3, s*0.3, s*0.3, -s*0.3, s, -s*0.3, -s*0.3, -s*0.3,
+		x+s*0.7, y+s*0.3, s*0.3, s*0.4, x+s, y+s*0.3, -s*0.3, s*0.4)
+}
+
+// lockIconPath returns the SVG path data for a padlock whose top-left
+// corner is at x, y.
+func lockIconPath(x, y float64) string {
+	s := trackStateIconSize
+	return fmt.Sprintf("M %.2f %.2f h %.2f v %.2f h %.2f Z M %.2f %.2f v %.2f a %.2f %.2f 0 0 1 %.2f 0 v %.2f",
+		x+s*0.1, y+s*0.45, s*0.8, s*0.55, -s*0.8,
+		x+s*0.25, y+s*0.45, -s*0.15, s*0.25, s*0.25, s*0.5, s*0.15)
+}
+
+// drawTrackState draws mute and lock icons to the left of a track's label,
+// which ends at labelX.
+func (e *Encoder) drawTrackState(builder *SVGBuilder, label string, labelX, y float64, muted, locked bool) error {
+	x := labelX - estimateTextWidth(label, float64(e.theme.fontSize(e.trackFontSize)))
+	iconY := y - trackStateIconSize/2
+	if locked {
+		x -= trackStateIconGap + trackStateIconSize
+		if err := builder.WritePathWithTitle(lockIconPath(x, iconY), "none", e.theme.Text, 1, "", "track-state lock-icon", "Locked"); err != nil {
+			return err
+		}
+	}
+	if muted {
+		x -= trackStateIconGap + trackStateIconSize
+		if err := builder.WritePathWithTitle(muteIconPath(x, iconY), "none", e.theme.Text, 1, "", "track-state mute-icon", "Muted"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
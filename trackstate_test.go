@@ -0,0 +1,91 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Contributors to the OpenTimelineIO project
+
+package svg
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/Avalanche-io/gotio"
+)
+
+func TestMetadataFlag(t *testing.T) {
+	tests := []struct {
+		value    any
+		expected bool
+	}{
+		{true, true},
+		{false, false},
+		{"true", true},
+		{"1", true},
+		{"no", false},
+		{1, false},
+		{nil, false},
+	}
+
+	for _, tt := range tests {
+		if result := metadataFlag(map[string]any{"muted": tt.value}, "muted"); result != tt.expected {
+			t.Errorf("metadataFlag(%v) = %v, want %v", tt.value, result, tt.expected)
+		}
+	}
+	if metadataFlag(map[string]any{"": true}, "") {
+		t.Error("An empty key should never be set")
+	}
+}
+
+func TestTrackState(t *testing.T) {
+	timeline := gotio.NewTimeline("State", nil, nil)
+	tracks := []*gotio.Track{
+		gotio.NewTrack("V1", nil, gotio.TrackKindVideo, gotio.AnyDictionary{"locked": true}, nil),
+		gotio.NewTrack("A1", nil, gotio.TrackKindAudio, gotio.AnyDictionary{"muted": true, "hidden": "true"}, nil),
+		gotio.NewTrack("A2", nil, gotio.TrackKindAudio, nil, nil),
+	}
+	for _, track := range tracks {
+		if err := track.AppendChild(newTestClip("Clip "+track.Name(), 24, 24)); err != nil {
+			t.Fatalf("Failed to append clip: %v", err)
+		}
+		if err := timeline.Tracks().AppendChild(track); err != nil {
+			t.Fatalf("Failed to append track: %v", err)
+		}
+	}
+
+	encode := func(opts ...Option) string {
+		var buf bytes.Buffer
+		if err := NewEncoder(&buf, opts...).Encode(timeline); err != nil {
+			t.Fatalf("Encode failed: %v", err)
+		}
+		return buf.String()
+	}
+
+	svg := encode()
+	if n := strings.Count(svg, `class="track-state lock-icon"`); n != 1 {
+		t.Errorf("Expected 1 lock icon, found %d", n)
+	}
+	if n := strings.Count(svg, `class="track-state mute-icon"`); n != 1 {
+		t.Errorf("Expected 1 mute icon, found %d", n)
+	}
+	if n := strings.Count(svg, `<g class="muted">`); n != 1 {
+		t.Errorf("Expected the muted track's items to be dimmed, found %d dimmed groups", n)
+	}
+	group := strings.Index(svg, `<g class="muted">`)
+	if !(strings.Index(svg, `id="clip-Clip_V1"`) < group && group < strings.Index(svg, `id="clip-Clip_A1"`)) {
+		t.Error("Muted group should hold the muted track's clips")
+	}
+
+	// Icons sit to the left of the label, which ends 10px left of the tracks
+	labelWidth := estimateTextWidth("V1", FontSize)
+	lockX := float64(MarginLeft-10) - labelWidth - trackStateIconGap - trackStateIconSize
+	if !strings.Contains(svg, `d="`+lockIconPath(lockX, float64(MarginTop+RulerHeight)+float64(TrackHeight)/2-trackStateIconSize/2)+`"`) {
+		t.Error("Lock icon not placed beside the track label")
+	}
+
+	svg = encode(WithTrackStateKeys("hidden", ""))
+	if strings.Contains(svg, "lock-icon") {
+		t.Error("An empty lock key should ignore the lock state")
+	}
+	if !strings.Contains(svg, "mute-icon") {
+		t.Error("Mute state not read from the configured key")
+	}
+}
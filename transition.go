@@ -0,0 +1,77 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Contributors to the OpenTimelineIO project
+
+package svg
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Avalanche-io/gotio"
+)
+
+// TransitionStyle selects how transitions are drawn.
+type TransitionStyle int
+
+const (
+	// TransitionStyleStraight draws transitions with straight lines, in a
+	// shape that depends on the transition type.
+	TransitionStyleStraight TransitionStyle = iota
+	// TransitionStyleCurved draws transitions as two crossing cubic bezier
+	// curves, depicting the outgoing and incoming media cross-fading.
+	TransitionStyleCurved
+)
+
+// transitionShape is the glyph used to draw a transition.
+type transitionShape int
+
+const (
+	// shapeDiagonal is a single line from bottom-left to top-right.
+	shapeDiagonal transitionShape = iota
+	// shapeCross is two crossing diagonals, for dissolves.
+	shapeCross
+	// shapeChevron is a right-pointing chevron, for wipes.
+	shapeChevron
+	// shapeCurves is two crossing S-curves, for the curved style.
+	shapeCurves
+)
+
+// transitionShapes maps OTIO transition types to their glyph. Types not
+// listed here are drawn as a diagonal, unless their name marks them as a
+// wipe.
+var transitionShapes = map[string]transitionShape{
+	gotio.TransitionTypeSMPTEDissolve: shapeCross,
+}
+
+// transitionShapeFor returns the glyph for a transition.
+func transitionShapeFor(transition *gotio.Transition) transitionShape {
+	if shape, ok := transitionShapes[transition.TransitionType()]; ok {
+		return shape
+	}
+	if strings.Contains(strings.ToLower(transition.TransitionType()+" "+transition.Name()), "wipe") {
+		return shapeChevron
+	}
+	return shapeDiagonal
+}
+
+// transitionPath returns the SVG path data for a transition glyph filling
+// the given rectangle.
+func transitionPath(shape transitionShape, x, y, width, height float64) string {
+	left, right := x, x+width
+	top, bottom := y, y+height
+	switch shape {
+	case shapeCross:
+		return fmt.Sprintf("M %.2f %.2f L %.2f %.2f M %.2f %.2f L %.2f %.2f",
+			left, bottom, right, top, left, top, right, bottom)
+	case shapeCurves:
+		mid := x + width/2
+		return fmt.Sprintf("M %.2f %.2f C %.2f %.2f %.2f %.2f %.2f %.2f M %.2f %.2f C %.2f %.2f %.2f %.2f %.2f %.2f",
+			left, top, mid, top, mid, bottom, right, bottom,
+			left, bottom, mid, bottom, mid, top, right, top)
+	case shapeChevron:
+		return fmt.Sprintf("M %.2f %.2f L %.2f %.2f L %.2f %.2f",
+			left, top, right, y+height/2, left, bottom)
+	default:
+		return fmt.Sprintf("M %.2f %.2f L %.2f %.2f", left, bottom, right, top)
+	}
+}
@@ -0,0 +1,142 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Contributors to the OpenTimelineIO project
+
+package svg
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/Avalanche-io/gotio"
+	"github.com/Avalanche-io/gotio/opentime"
+)
+
+func TestTransitionShapeFor(t *testing.T) {
+	half := opentime.NewRationalTime(12, 24)
+	tests := []struct {
+		name     string
+		kind     string
+		expected transitionShape
+	}{
+		{"Dissolve", gotio.TransitionTypeSMPTEDissolve, shapeCross},
+		{"Barn Wipe", gotio.TransitionTypeCustom, shapeChevron},
+		{"Push", gotio.TransitionTypeCustom, shapeDiagonal},
+	}
+
+	for _, tt := range tests {
+		transition := gotio.NewTransition(tt.name, tt.kind, half, half, nil)
+		if shape := transitionShapeFor(transition); shape != tt.expected {
+			t.Errorf("transitionShapeFor(%s) = %d, want %d", tt.name, shape, tt.expected)
+		}
+	}
+}
+
+func TestTransitionPath(t *testing.T) {
+	tests := []struct {
+		shape    transitionShape
+		expected string
+	}{
+		{shapeDiagonal, "M 0.00 10.00 L 20.00 0.00"},
+		{shapeCross, "M 0.00 10.00 L 20.00 0.00 M 0.00 0.00 L 20.00 10.00"},
+		{shapeChevron, "M 0.00 0.00 L 20.00 5.00 L 0.00 10.00"},
+		{shapeCurves, "M 0.00 0.00 C 10.00 0.00 10.00 10.00 20.00 10.00 M 0.00 10.00 C 10.00 10.00 10.00 0.00 20.00 0.00"},
+	}
+
+	for _, tt := range tests {
+		if path := transitionPath(tt.shape, 0, 0, 20, 10); path != tt.expected {
+			t.Errorf("transitionPath(%d) = %q, want %q", tt.shape, path, tt.expected)
+		}
+	}
+}
+
+func TestTransitionLabel(t *testing.T) {
+	timeline := gotio.NewTimeline("Transition Label", nil, nil)
+	track := gotio.NewTrack("V1", nil, gotio.TrackKindVideo, nil, nil)
+	children := []gotio.Composable{
+		newTestClip("A", 96, 24),
+		gotio.NewTransition("Dissolve", gotio.TransitionTypeSMPTEDissolve,
+			opentime.NewRationalTime(24, 24), opentime.NewRationalTime(24, 24), nil),
+		newTestClip("B", 96, 24),
+	}
+	for _, child := range children {
+		if err := track.AppendChild(child); err != nil {
+			t.Fatalf("Failed to append child: %v", err)
+		}
+	}
+	if err := timeline.Tracks().AppendChild(track); err != nil {
+		t.Fatalf("Failed to append track: %v", err)
+	}
+
+	svg := encodeString(t, timeline, nil)
+	if !strings.Contains(svg, `class="transition-label" dominant-baseline="middle">Dissolve</text>`) {
+		t.Error("SVG missing transition name label")
+	}
+}
+
+func TestCurvedTransitionStyle(t *testing.T) {
+	timeline := gotio.NewTimeline("Curved", nil, nil)
+	track := gotio.NewTrack("V1", nil, gotio.TrackKindVideo, nil, nil)
+	children := []gotio.Composable{
+		newTestClip("A", 96, 24),
+		gotio.NewTransition("Dissolve", gotio.TransitionTypeSMPTEDissolve,
+			opentime.NewRationalTime(12, 24), opentime.NewRationalTime(12, 24), nil),
+		newTestClip("B", 96, 24),
+	}
+	for _, child := range children {
+		if err := track.AppendChild(child); err != nil {
+			t.Fatalf("Failed to append child: %v", err)
+		}
+	}
+	if err := timeline.Tracks().AppendChild(track); err != nil {
+		t.Fatalf("Failed to append track: %v", err)
+	}
+
+	straight := encodeString(t, timeline, nil)
+	if strings.Contains(straight, " C ") {
+		t.Error("Default transition style should not use curves")
+	}
+
+	curved := encodeString(t, timeline, func(enc *Encoder) {
+		enc.SetTransitionStyle(TransitionStyleCurved)
+	})
+	if !strings.Contains(curved, `class="transition"`) || strings.Count(curved, " C ") != 2 {
+		t.Error("Curved transition style should draw two bezier curves")
+	}
+}
+
+func TestTransitionLabelScaled(t *testing.T) {
+	timeline := gotio.NewTimeline("Transition Label", nil, nil)
+	track := gotio.NewTrack("V1", nil, gotio.TrackKindVideo, nil, nil)
+	children := []gotio.Composable{
+		newTestClip("A", 96, 24),
+		gotio.NewTransition("Dissolve", gotio.TransitionTypeSMPTEDissolve,
+			opentime.NewRationalTime(24, 24), opentime.NewRationalTime(24, 24), nil),
+		newTestClip("B", 96, 24),
+	}
+	for _, child := range children {
+		if err := track.AppendChild(child); err != nil {
+			t.Fatalf("Failed to append child: %v", err)
+		}
+	}
+	if err := timeline.Tracks().AppendChild(track); err != nil {
+		t.Fatalf("Failed to append track: %v", err)
+	}
+
+	// The label sits one font size below the top of the transition
+	labelY := regexp.MustCompile(`y="([0-9.]+)" text-anchor="middle" class="transition-label"`)
+	plain := labelY.FindStringSubmatch(encodeString(t, timeline, nil))
+	scaled := labelY.FindStringSubmatch(encodeString(t, timeline, func(enc *Encoder) {
+		enc.SetTheme(HighContrastTheme())
+	}))
+	if plain == nil || scaled == nil {
+		t.Fatal("Missing transition label")
+	}
+	top, _ := strconv.ParseFloat(plain[1], 64)
+	top -= noteFontSize
+	if want := fmt.Sprintf("%.2f", top+13); scaled[1] != want {
+		t.Errorf("Scaled transition label at y=%s, want %s", scaled[1], want)
+	}
+}
@@ -0,0 +1,109 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Contributors to the OpenTimelineIO project
+
+package svg
+
+import (
+	"math"
+
+	"github.com/Avalanche-io/gotio/opentime"
+)
+
+// Orientation selects which way time runs across the canvas.
+type Orientation int
+
+const (
+	// OrientationHorizontal draws tracks as rows, with time running left to
+	// right below a ruler.
+	OrientationHorizontal Orientation = iota
+	// OrientationVertical draws tracks as columns, with time running top to
+	// bottom beside a ruler down the left side, like a Gantt chart turned on
+	// its side. Track names head the columns, and the left margin holds the
+	// ruler. Markers, the minimap, the scale bar ruler style and the removed
+	// clips of EncodeDiff are only drawn horizontally.
+	OrientationVertical
+)
+
+// verticalRulerWidth is the widest the ruler of a vertical layout gets,
+// taken from the left margin.
+const verticalRulerWidth = 80
+
+// layoutVertical lays out tracks as columns, with time running top to
+// bottom. Track names sit in a row the height of the ruler above the
+// columns, and the ruler takes the left margin. Auto height sizes the
+// canvas width to fit the columns, while a fixed scale or zoom sizes its
+// height to fit the time axis.
+func (e *Encoder) layoutVertical(l *layout, duration opentime.RationalTime) {
+	l.vertical = true
+	l.rulerHeight = RulerHeight
+	l.dualRuler = false
+	l.marginBottom = e.marginBottom
+
+	// Calculate canvas width and per-column width
+	l.width = e.width
+	l.trackHeight = e.trackHeight
+	l.lanesLeft = float64(e.marginLeft)
+	if e.autoHeight {
+		l.width = e.marginLeft + l.numTracks*l.trackHeight + e.marginRight
+	} else {
+		l.trackHeight = e.laneSize(float64(l.width-e.marginLeft-e.marginRight), l.numTracks)
+	}
+
+	l.height = e.height
+	l.contentLeft = float64(l.marginTop + l.rulerHeight)
+	if e.scaleTimeAxis(l, duration, float64(l.height-l.marginBottom)-l.contentLeft) {
+		l.height = int(l.contentLeft) + int(math.Ceil(l.contentWidth)) + l.marginBottom
+	}
+}
+
+// verticalRulerX returns the x position of the left edge of the ruler of a
+// vertical layout, which ends at the first column.
+func (e *Encoder) verticalRulerX(l *layout) float64 {
+	return math.Max(l.lanesLeft-verticalRulerWidth, 0)
+}
+
+// drawVerticalRuler draws the ruler of a vertical layout down the left of
+// the columns, with each label below its tick.
+func (e *Encoder) drawVerticalRuler(builder *SVGBuilder, l *layout) error {
+	if err := builder.StartGroup(builder.UniqueID("time-ruler"), "ruler"); err != nil {
+		return err
+	}
+
+	left := e.verticalRulerX(l)
+	right := l.lanesLeft
+	if err := builder.WriteRect(left, l.contentLeft, right-left, l.contentWidth, e.theme.TrackLabelBg, e.theme.Grid, "", "ruler-bg", ""); err != nil {
+		return err
+	}
+
+	// Labels go above the tick if they would spill past the end of the ruler
+	rulerBottom := l.contentLeft + l.contentWidth
+	offset := float64(e.theme.fontSize(e.rulerFontSize))/2 + labelLineGap
+	for _, time := range e.rulerTicks(l) {
+		y := l.contentLeft + time*l.timeScale
+		if err := builder.WriteLine(left, y, right, y, e.theme.Grid, 1, "tick"); err != nil {
+			return err
+		}
+
+		labelY := y + offset
+		if labelY+float64(e.theme.fontSize(e.rulerFontSize))/2 > rulerBottom {
+			labelY = y - offset
+		}
+		timeLabel := e.rulerLabel(l, time)
+		if err := builder.WriteText(right-labelPadding, labelY, timeLabel, "end", "", "ruler-text"); err != nil {
+			return err
+		}
+	}
+
+	// Draw shorter, unlabeled minor ticks between the major ones
+	if minors := e.minorTicks(l); len(minors) > 0 {
+		minorLeft := right - (right-left)*0.25
+		for _, time := range minors {
+			y := l.contentLeft + time*l.timeScale
+			if err := builder.WriteLine(minorLeft, y, right, y, e.theme.Grid, 1, "minor-tick"); err != nil {
+				return err
+			}
+		}
+	}
+
+	return builder.EndGroup()
+}
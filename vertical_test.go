@@ -0,0 +1,119 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Contributors to the OpenTimelineIO project
+
+package svg
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/Avalanche-io/gotio"
+	"github.com/Avalanche-io/gotio/opentime"
+)
+
+// buildColumns builds a 4 second timeline with a video track holding two
+// clips around a gap, and an audio track holding one clip.
+func buildColumns(t *testing.T) *gotio.Timeline {
+	t.Helper()
+	timeline := gotio.NewTimeline("Columns", nil, nil)
+	video := gotio.NewTrack("V1", nil, gotio.TrackKindVideo, nil, nil)
+	audio := gotio.NewTrack("A1", nil, gotio.TrackKindAudio, nil, nil)
+	for _, item := range []gotio.Composable{
+		newTestClip("Shot 1", 48, 24),
+		gotio.NewGapWithDuration(opentime.NewRationalTime(24, 24)),
+		newTestClip("Shot 2", 24, 24),
+	} {
+		if err := video.AppendChild(item); err != nil {
+			t.Fatalf("Failed to append item: %v", err)
+		}
+	}
+	if err := audio.AppendChild(newTestClip("Music", 96, 24)); err != nil {
+		t.Fatalf("Failed to append clip: %v", err)
+	}
+	for _, track := range []*gotio.Track{video, audio} {
+		if err := timeline.Tracks().AppendChild(track); err != nil {
+			t.Fatalf("Failed to append track: %v", err)
+		}
+	}
+	return timeline
+}
+
+func TestOrientationVertical(t *testing.T) {
+	var buf bytes.Buffer
+	enc := NewEncoder(&buf)
+	enc.SetOrientation(OrientationVertical)
+	enc.SetPlayhead(opentime.NewRationalTime(48, 24))
+	enc.SetShowGrid(true)
+	enc.SetRecordLayout(true)
+	if err := enc.Encode(buildColumns(t)); err != nil {
+		t.Fatalf("Encode failed: %v", err)
+	}
+	svg := buf.String()
+
+	// Time runs down from below the row of track names to the bottom margin
+	top := float64(MarginTop + RulerHeight)
+	length := float64(DefaultHeight-MarginBottom) - top
+	scale := length / 4
+
+	// Tracks are columns of the full track height, starting at the left margin
+	want := fmt.Sprintf(`<rect x="%d.00" y="%.2f" width="%d.00" height="%.2f" fill="%s33"`, MarginLeft+TrackHeight, top, TrackHeight, length, AudioTrackColor)
+	if !strings.Contains(svg, want) {
+		t.Errorf("Audio track not drawn as the second column, want %s", want)
+	}
+	if !strings.Contains(svg, fmt.Sprintf(`<text x="%d.00" y="%d.00" text-anchor="middle" class="track-label" dominant-baseline="middle">V1</text>`, MarginLeft+TrackHeight/2, MarginTop+RulerHeight/2)) {
+		t.Error("Track name should head its column")
+	}
+
+	// Items are inset across their column, not along time
+	want = fmt.Sprintf(`<rect x="%d.00" y="%.2f" width="%d.00" height="%.2f" fill="%s" stroke="#333333" id="clip-Shot_2"`, MarginLeft+2, top+3*scale, TrackHeight-4, scale, VideoTrackColor)
+	if !strings.Contains(svg, want) {
+		t.Errorf("Clip not placed down its column, want %s", want)
+	}
+	if got, want := enc.LastLayout()["clip-Shot_2"], (Rect{X: MarginLeft + 2, Y: top + 3*scale, Width: TrackHeight - 4, Height: scale}); got != want {
+		t.Errorf("Recorded clip rect = %+v, want %+v", got, want)
+	}
+	if !strings.Contains(svg, `id="gap-V1-1"`) {
+		t.Error("Gap not drawn")
+	}
+
+	// The ruler runs down the left margin, with horizontal ticks
+	rulerLeft := float64(MarginLeft - verticalRulerWidth)
+	if !strings.Contains(svg, fmt.Sprintf(`<line x1="%.2f" y1="%.2f" x2="%d.00" y2="%.2f" stroke="#CCCCCC" stroke-width="1.00" class="tick" />`, rulerLeft, top+scale, MarginLeft, top+scale)) {
+		t.Error("Ruler tick not drawn across the left margin")
+	}
+	if !strings.Contains(svg, fmt.Sprintf(`<line x1="%d.00" y1="%.2f" x2="%d.00" y2="%.2f"`, MarginLeft, top+scale, MarginLeft+2*TrackHeight, top+scale)) {
+		t.Error("Grid line not drawn across the columns")
+	}
+	if !strings.Contains(svg, fmt.Sprintf(`<line x1="%.2f" y1="%.2f" x2="%d.00" y2="%.2f" stroke="%s" stroke-width="2.00" class="playhead-line" />`, rulerLeft, top+2*scale, MarginLeft+2*TrackHeight, top+2*scale, PlayheadColor)) {
+		t.Error("Playhead not drawn across the columns")
+	}
+}
+
+func TestOrientationVerticalScale(t *testing.T) {
+	// A fixed scale sizes the canvas height to fit
+	svg := encodeString(t, buildColumns(t), func(enc *Encoder) {
+		enc.SetOrientation(OrientationVertical)
+		enc.SetPixelsPerSecond(50)
+		enc.SetAutoHeight(true)
+	})
+	wantHeight := MarginTop + RulerHeight + 200 + MarginBottom
+	wantWidth := MarginLeft + 2*TrackHeight + MarginRight
+	if !strings.Contains(svg, fmt.Sprintf(`width="%d" height="%d"`, wantWidth, wantHeight)) {
+		t.Errorf("SVG not sized to fit %dx%d", wantWidth, wantHeight)
+	}
+	if !strings.Contains(svg, `height="100.00" fill="`+VideoTrackColor+`" stroke="#333333" id="clip-Shot_1"`) {
+		t.Error("Clip not drawn at fixed scale")
+	}
+}
+
+func TestOrientationHorizontalDefault(t *testing.T) {
+	timeline := buildColumns(t)
+	svg := encodeString(t, timeline, func(enc *Encoder) {
+		enc.SetOrientation(OrientationHorizontal)
+	})
+	if svg != encodeString(t, timeline, nil) {
+		t.Error("Horizontal orientation should be the default")
+	}
+}
@@ -0,0 +1,163 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Contributors to the OpenTimelineIO project
+
+package svg
+
+import (
+	"fmt"
+	"hash/fnv"
+	"math"
+	"strings"
+
+	"github.com/Avalanche-io/gotio"
+	"github.com/Avalanche-io/gotio/opentime"
+)
+
+const (
+	// waveformStep is the horizontal distance between waveform peaks.
+	waveformStep = 3.0
+	// waveformMaxPoints caps the points in one waveform, so very wide
+	// clips don't bloat the document.
+	waveformMaxPoints = 2000
+	// waveformMinAmplitude is the smallest peak, as a fraction of the
+	// largest, so the waveform never goes flat.
+	waveformMinAmplitude = 0.2
+)
+
+// WaveformProvider supplies the waveform of audio clips, typically from an
+// analysis of their media.
+type WaveformProvider interface {
+	// Peaks returns the peak levels of a clip from 0 to 1, spread evenly
+	// over the clip's duration, or false if it has no waveform for the clip.
+	Peaks(clip *gotio.Clip) ([]float64, bool)
+}
+
+// waveformSeed derives a seed from a clip's name and duration, so each clip
+// always gets the same waveform.
+func waveformSeed(clip *gotio.Clip, rng opentime.TimeRange) uint64 {
+	h := fnv.New64a()
+	fmt.Fprintf(h, "%s/%g", clip.Name(), rng.Duration().ToSeconds())
+	return h.Sum64()
+}
+
+// waveformPoints returns the points of a zig-zag line across a rectangle,
+// alternating above and below its vertical center with pseudo-random peaks
+// drawn from seed.
+func waveformPoints(seed uint64, x, y, width, height float64) string {
+	if width <= 0 || height <= 0 {
+		return ""
+	}
+	step := math.Max(waveformStep, width/waveformMaxPoints)
+	n := int(width / step)
+	if n < 1 {
+		return ""
+	}
+
+	// xorshift64 needs a non-zero state
+	state := seed
+	if state == 0 {
+		state = 1
+	}
+	next := func() float64 {
+		state ^= state << 13
+		state ^= state >> 7
+		state ^= state << 17
+		return float64(state>>11) / (1 << 53)
+	}
+
+	center := y + height/2
+	var points strings.Builder
+	fmt.Fprintf(&points, "%.2f,%.2f", x, center)
+	for i := 1; i < n; i++ {
+		amplitude := (waveformMinAmplitude + (1-waveformMinAmplitude)*next()) * height / 2
+		if i%2 == 1 {
+			amplitude = -amplitude
+		}
+		fmt.Fprintf(&points, " %.2f,%.2f", x+float64(i)*step, center+amplitude)
+	}
+	fmt.Fprintf(&points, " %.2f,%.2f", x+width, center)
+	return points.String()
+}
+
+// peakPoints returns the outline of a waveform mirrored about the vertical
+// center of a rectangle, running left to right along the top and back along
+// the bottom. Peaks are clamped to 0 to 1, and merged by taking the largest
+// of neighbors when there are more than waveformMaxPoints.
+func peakPoints(peaks []float64, x, y, width, height float64) string {
+	if len(peaks) == 0 || width <= 0 || height <= 0 {
+		return ""
+	}
+	if len(peaks) > waveformMaxPoints {
+		merged := make([]float64, waveformMaxPoints)
+		for i, p := range peaks {
+			j := i * waveformMaxPoints / len(peaks)
+			merged[j] = math.Max(merged[j], p)
+		}
+		peaks = merged
+	}
+	levels := make([]float64, len(peaks))
+	for i, p := range peaks {
+		if p > 0 { // false for NaN
+			levels[i] = math.Min(p, 1)
+		}
+	}
+
+	// A single peak spans the whole clip
+	xs := make([]float64, len(levels))
+	for i := range levels {
+		xs[i] = x
+		if len(levels) > 1 {
+			xs[i] += width * float64(i) / float64(len(levels)-1)
+		}
+	}
+	if len(levels) == 1 {
+		xs = append(xs, x+width)
+		levels = append(levels, levels[0])
+	}
+
+	center := y + height/2
+	var points strings.Builder
+	for i, level := range levels {
+		if i > 0 {
+			points.WriteByte(' ')
+		}
+		fmt.Fprintf(&points, "%.2f,%.2f", xs[i], center-level*height/2)
+	}
+	for i := len(levels) - 1; i >= 0; i-- {
+		fmt.Fprintf(&points, " %.2f,%.2f", xs[i], center+levels[i]*height/2)
+	}
+	return points.String()
+}
+
+// drawWaveform draws a waveform across the middle of an audio clip, behind
+// its label. It uses the peaks from the waveform provider when it has them,
+// and otherwise a placeholder if enabled.
+func (e *Encoder) drawWaveform(builder *SVGBuilder, clip *gotio.Clip, rng opentime.TimeRange, x, y, width, height float64, trackColor string) error {
+	color := contrastColor(trackColor)
+	if color == "" {
+		color = e.theme.ClipText
+	}
+
+	// Keep the waveform clear of the clip's border
+	inset := 2.0 * labelPadding
+	x, y = x+labelPadding, y+inset
+	width, height = width-2*labelPadding, height-2*inset
+
+	if e.waveformProvider != nil {
+		if peaks, ok := e.waveformProvider.Peaks(clip); ok {
+			points := peakPoints(peaks, x, y, width, height)
+			if points == "" {
+				return nil
+			}
+			return builder.WritePolygon(points, color, "", 0, "waveform waveform-peaks")
+		}
+	}
+	if !e.audioWaveform {
+		return nil
+	}
+	points := waveformPoints(waveformSeed(clip, rng), x, y, width, height)
+	if points == "" {
+		return nil
+	}
+	return builder.WritePolyline(points, color, 1, "waveform")
+}
@@ -0,0 +1,173 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Contributors to the OpenTimelineIO project
+
+package svg
+
+import (
+	"math"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/Avalanche-io/gotio"
+)
+
+func TestWaveformPoints(t *testing.T) {
+	points := waveformPoints(42, 10, 20, 100, 40)
+	if points != waveformPoints(42, 10, 20, 100, 40) {
+		t.Error("Waveform should be the same for the same seed")
+	}
+	if points == waveformPoints(43, 10, 20, 100, 40) {
+		t.Error("Waveform should differ for different seeds")
+	}
+
+	pairs := strings.Fields(points)
+	if len(pairs) < 30 {
+		t.Fatalf("Expected a point every few pixels, got %d points", len(pairs))
+	}
+	for i, pair := range pairs {
+		x, y, ok := strings.Cut(pair, ",")
+		if !ok {
+			t.Fatalf("Malformed point %q", pair)
+		}
+		px, _ := strconv.ParseFloat(x, 64)
+		py, _ := strconv.ParseFloat(y, 64)
+		if px < 10 || px > 110 || py < 20 || py > 60 {
+			t.Errorf("Point %q outside the rectangle", pair)
+		}
+		// Peaks alternate above and below the center
+		if i > 0 && i < len(pairs)-1 {
+			if above := py < 40; above != (i%2 == 1) {
+				t.Errorf("Point %d (%q) on the wrong side of the center", i, pair)
+			}
+		}
+	}
+
+	if n := len(strings.Fields(waveformPoints(1, 0, 0, 1e6, 40))); n > waveformMaxPoints+1 {
+		t.Errorf("Wide waveform has %d points, want at most %d", n, waveformMaxPoints+1)
+	}
+	if waveformPoints(1, 0, 0, 0, 40) != "" {
+		t.Error("Zero-width waveform should be empty")
+	}
+}
+
+func TestAudioWaveform(t *testing.T) {
+	timeline := gotio.NewTimeline("Waveform", nil, nil)
+	video := gotio.NewTrack("V1", nil, gotio.TrackKindVideo, nil, nil)
+	audio := gotio.NewTrack("A1", nil, gotio.TrackKindAudio, nil, nil)
+	if err := video.AppendChild(newTestClip("Picture", 48, 24)); err != nil {
+		t.Fatalf("Failed to append clip: %v", err)
+	}
+	if err := audio.AppendChild(newTestClip("Sound", 48, 24)); err != nil {
+		t.Fatalf("Failed to append clip: %v", err)
+	}
+	for _, track := range []*gotio.Track{video, audio} {
+		if err := timeline.Tracks().AppendChild(track); err != nil {
+			t.Fatalf("Failed to append track: %v", err)
+		}
+	}
+
+	svg := encodeString(t, timeline, nil)
+	if strings.Contains(svg, "<polyline") {
+		t.Error("Waveform should be off by default")
+	}
+
+	svg = encodeString(t, timeline, func(enc *Encoder) {
+		enc.SetAudioWaveform(true)
+	})
+	if n := strings.Count(svg, `class="waveform"`); n != 1 {
+		t.Errorf("Expected a waveform on the audio clip only, found %d", n)
+	}
+	if svg != encodeString(t, timeline, func(enc *Encoder) { enc.SetAudioWaveform(true) }) {
+		t.Error("Waveform should be reproducible")
+	}
+
+	// The label is drawn over the waveform
+	if strings.Index(svg, `class="waveform"`) > strings.Index(svg, ">Sound</text>") {
+		t.Error("Waveform should be drawn behind the clip label")
+	}
+}
+
+// peaksByName provides peaks for clips by name.
+type peaksByName map[string][]float64
+
+func (p peaksByName) Peaks(clip *gotio.Clip) ([]float64, bool) {
+	peaks, ok := p[clip.Name()]
+	return peaks, ok
+}
+
+func TestPeakPoints(t *testing.T) {
+	got := peakPoints([]float64{0, 1, 0.5}, 0, 0, 100, 40)
+	want := "0.00,20.00 50.00,0.00 100.00,10.00 100.00,30.00 50.00,40.00 0.00,20.00"
+	if got != want {
+		t.Errorf("peakPoints = %q, want %q", got, want)
+	}
+
+	// Out of range peaks are clamped
+	got = peakPoints([]float64{-1, math.NaN(), 2}, 0, 0, 100, 40)
+	want = "0.00,20.00 50.00,20.00 100.00,0.00 100.00,40.00 50.00,20.00 0.00,20.00"
+	if got != want {
+		t.Errorf("peakPoints with out of range peaks = %q, want %q", got, want)
+	}
+
+	got = peakPoints([]float64{0.5}, 0, 0, 100, 40)
+	want = "0.00,10.00 100.00,10.00 100.00,30.00 0.00,30.00"
+	if got != want {
+		t.Errorf("peakPoints with one peak = %q, want %q", got, want)
+	}
+
+	many := make([]float64, 10*waveformMaxPoints)
+	many[len(many)-1] = 1
+	points := strings.Fields(peakPoints(many, 0, 0, 100, 40))
+	if len(points) != 2*waveformMaxPoints {
+		t.Errorf("Expected %d points after merging, got %d", 2*waveformMaxPoints, len(points))
+	}
+	if points[waveformMaxPoints-1] != "100.00,0.00" {
+		t.Errorf("Merging should keep the largest peak, got %q", points[waveformMaxPoints-1])
+	}
+
+	if peakPoints(nil, 0, 0, 100, 40) != "" {
+		t.Error("No peaks should give no points")
+	}
+}
+
+func TestWaveformProvider(t *testing.T) {
+	timeline := gotio.NewTimeline("Peaks", nil, nil)
+	audio := gotio.NewTrack("A1", nil, gotio.TrackKindAudio, nil, nil)
+	video := gotio.NewTrack("V1", nil, gotio.TrackKindVideo, nil, nil)
+	for _, name := range []string{"Dialog", "Music"} {
+		if err := audio.AppendChild(newTestClip(name, 48, 24)); err != nil {
+			t.Fatalf("Failed to append clip: %v", err)
+		}
+	}
+	if err := video.AppendChild(newTestClip("Picture", 96, 24)); err != nil {
+		t.Fatalf("Failed to append clip: %v", err)
+	}
+	for _, track := range []*gotio.Track{video, audio} {
+		if err := timeline.Tracks().AppendChild(track); err != nil {
+			t.Fatalf("Failed to append track: %v", err)
+		}
+	}
+
+	provider := peaksByName{
+		"Dialog":  {0.2, 0.8, 0.4},
+		"Picture": {1, 1},
+	}
+	svg := encodeString(t, timeline, func(enc *Encoder) {
+		enc.SetWaveformProvider(provider)
+	})
+	if n := strings.Count(svg, `class="waveform waveform-peaks"`); n != 1 {
+		t.Errorf("Expected peaks on the one audio clip provided, found %d", n)
+	}
+	if strings.Contains(svg, "<polyline") {
+		t.Error("Clips without peaks should be plain without the placeholder")
+	}
+
+	svg = encodeString(t, timeline, func(enc *Encoder) {
+		enc.SetWaveformProvider(provider)
+		enc.SetAudioWaveform(true)
+	})
+	if strings.Count(svg, "<polygon") != 1 || strings.Count(svg, "<polyline") != 1 {
+		t.Error("Clips without peaks should fall back to the placeholder when enabled")
+	}
+}